@@ -0,0 +1,71 @@
+package gopensky
+
+import "time"
+
+// Timestamp returns the time which the state vectors are associated with.
+func (s *States) Timestamp() time.Time {
+	return time.Unix(s.Time, 0)
+}
+
+// PositionTime returns the time of the last position update, or the zero time if unknown.
+func (s *StateVector) PositionTime() time.Time {
+	if s.TimePosition == nil {
+		return time.Time{}
+	}
+
+	return time.Unix(*s.TimePosition, 0)
+}
+
+// LastContactTime returns the time of the last update received from the transponder.
+func (s *StateVector) LastContactTime() time.Time {
+	return time.Unix(s.LastContact, 0)
+}
+
+// FirstSeenTime returns the estimated departure time of the flight.
+func (f *FlighData) FirstSeenTime() time.Time {
+	return time.Unix(f.FirstSeen, 0)
+}
+
+// LastSeenTime returns the estimated arrival time of the flight.
+func (f *FlighData) LastSeenTime() time.Time {
+	return time.Unix(f.LastSeen, 0)
+}
+
+// Duration returns the estimated flight duration between first and last seen.
+func (f *FlighData) Duration() time.Duration {
+	return time.Duration(f.LastSeen-f.FirstSeen) * time.Second
+}
+
+// StartedAt returns the time of the first waypoint.
+func (t *FlightTrack) StartedAt() time.Time {
+	return time.Unix(t.StartTime, 0)
+}
+
+// EndedAt returns the time of the last waypoint.
+func (t *FlightTrack) EndedAt() time.Time {
+	return time.Unix(t.EndTime, 0)
+}
+
+// Duration returns the duration of the track between the first and last waypoints.
+func (t *FlightTrack) Duration() time.Duration {
+	return time.Duration(t.EndTime-t.StartTime) * time.Second
+}
+
+// Timestamp returns the time which the waypoint is associated with.
+func (w *WayPoint) Timestamp() time.Time {
+	return time.Unix(w.Time, 0)
+}
+
+// EntryTime returns the time of the first waypoint in the geofence.
+func (v *GeofenceVisit) EntryTime() time.Time {
+	return time.Unix(v.Entry, 0)
+}
+
+// ExitTime returns the time of the first waypoint out of the geofence, zero time if the track ends in the geofence.
+func (v *GeofenceVisit) ExitTime() time.Time {
+	if v.Exit == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(v.Exit, 0)
+}
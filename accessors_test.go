@@ -0,0 +1,47 @@
+package gopensky_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Accessors", func() {
+	It("returns the state vector times", func() {
+		timePosition := int64(1518552800)
+		stvec := gopensky.StateVector{LastContact: 1518552809, TimePosition: &timePosition}
+
+		Expect(stvec.LastContactTime()).To(Equal(time.Unix(1518552809, 0)))
+		Expect(stvec.PositionTime()).To(Equal(time.Unix(1518552800, 0)))
+
+		stvec.TimePosition = nil
+		Expect(stvec.PositionTime().IsZero()).To(BeTrue())
+
+		states := gopensky.States{Time: 1518552809}
+		Expect(states.Timestamp().UTC()).To(Equal(time.Date(2018, 2, 13, 20, 13, 29, 0, time.UTC)))
+	})
+
+	It("returns the flight times and duration", func() {
+		flight := gopensky.FlighData{FirstSeen: 1689193028, LastSeen: 1689197805}
+
+		Expect(flight.FirstSeenTime()).To(Equal(time.Unix(1689193028, 0)))
+		Expect(flight.LastSeenTime()).To(Equal(time.Unix(1689197805, 0)))
+		Expect(flight.Duration()).To(Equal(79*time.Minute + 37*time.Second))
+	})
+
+	It("returns the track times and duration", func() {
+		track := gopensky.FlightTrack{
+			StartTime: 1689193028,
+			EndTime:   1689193088,
+			Path:      []gopensky.WayPoint{{Time: 1689193028}},
+		}
+
+		Expect(track.StartedAt()).To(Equal(time.Unix(1689193028, 0)))
+		Expect(track.EndedAt()).To(Equal(time.Unix(1689193088, 0)))
+		Expect(track.Duration()).To(Equal(time.Minute))
+		Expect(track.Path[0].Timestamp()).To(Equal(time.Unix(1689193028, 0)))
+	})
+})
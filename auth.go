@@ -0,0 +1,218 @@
+package gopensky
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	openSkyTokenURL = "https://auth.opensky-network.org/auth/realms/opensky-network/protocol/openid-connect/token"
+
+	// tokenRefreshMargin is how long before its expiry a cached token is refreshed.
+	tokenRefreshMargin  = 30 * time.Second
+	tokenRequestTimeout = 30 * time.Second
+)
+
+// Authenticator adds the credentials to the API requests of a connection.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// Invalidator is implemented by authenticators that cache credentials.
+// Invalidate is called when the API rejected a request with 401 Unauthorized,
+// the request is then retried once with a fresh credential.
+type Invalidator interface {
+	Invalidate()
+}
+
+// Identifier is implemented by authenticators to name the account they authenticate.
+// The identity is hashed into the cache keys, so that the cached responses are not shared between accounts.
+// The connections with an authenticator which doesn't implement it don't share their cached responses.
+type Identifier interface {
+	Identity() string
+}
+
+// WithAuthenticator sets the authenticator of the connection.
+// It replaces the basic authentication built from the NewConnection username and password.
+func WithAuthenticator(auth Authenticator) Option {
+	return func(c *Connection) error {
+		c.authenticator = auth
+
+		return nil
+	}
+}
+
+// WithClientCredentials authenticates the connection with OpenSky OAuth2 client credentials.
+func WithClientCredentials(clientID string, clientSecret string) Option {
+	return WithAuthenticator(NewClientCredentialsAuth(clientID, clientSecret, "", nil))
+}
+
+// BasicAuth authenticates requests with HTTP basic authentication (OpenSky legacy accounts).
+type BasicAuth struct {
+	username string
+	auth     string
+}
+
+// NewBasicAuth returns HTTP basic authenticator for the given username and password.
+func NewBasicAuth(username string, password string) *BasicAuth {
+	return &BasicAuth{
+		username: username,
+		auth:     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+	}
+}
+
+// Authenticate adds the basic authorization header to the request.
+func (b *BasicAuth) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Basic "+b.auth)
+
+	return nil
+}
+
+// Identity returns the username.
+func (b *BasicAuth) Identity() string {
+	return b.username
+}
+
+// ClientCredentialsAuth authenticates requests with an OAuth2 bearer token
+// obtained by the client credentials grant.
+// The token is cached and refreshed shortly before it expires.
+type ClientCredentialsAuth struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	client       *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+	now    func() time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// NewClientCredentialsAuth returns OAuth2 client credentials authenticator.
+// OpenSky token endpoint is used if tokenURL is empty and
+// a default HTTP client is used for the token requests if client is nil.
+func NewClientCredentialsAuth(clientID string, clientSecret string, tokenURL string, client *http.Client,
+) *ClientCredentialsAuth {
+	if tokenURL == "" {
+		tokenURL = openSkyTokenURL
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: tokenRequestTimeout}
+	}
+
+	return &ClientCredentialsAuth{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		tokenURL:     tokenURL,
+		client:       client,
+		now:          time.Now,
+	}
+}
+
+// Authenticate adds the bearer authorization header to the request,
+// a new token is requested if there is no valid cached one.
+func (a *ClientCredentialsAuth) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+// Invalidate drops the cached token.
+func (a *ClientCredentialsAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = ""
+	a.expiry = time.Time{}
+}
+
+// Identity returns the client ID.
+func (a *ClientCredentialsAuth) Identity() string {
+	return a.clientID
+}
+
+// Token returns the cached access token or requests a new one from the token endpoint.
+func (a *ClientCredentialsAuth) Token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && (a.expiry.IsZero() || a.now().Before(a.expiry.Add(-tokenRefreshMargin))) {
+		return a.token, nil
+	}
+
+	tokenResp, err := a.requestToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errTokenRequest, err)
+	}
+
+	a.token = tokenResp.AccessToken
+	a.expiry = time.Time{}
+
+	if tokenResp.ExpiresIn > 0 {
+		a.expiry = a.now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+
+	return a.token, nil
+}
+
+func (a *ClientCredentialsAuth) requestToken(ctx context.Context) (*tokenResponse, error) {
+	var tokenResp tokenResponse
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", a.clientID)
+	form.Set("client_secret", a.clientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s", http.StatusText(response.StatusCode), data) //nolint:err113
+	}
+
+	if err := json.Unmarshal(data, &tokenResp); err != nil {
+		return nil, fmt.Errorf("unmarshalling token response: %w", err)
+	}
+
+	if tokenResp.AccessToken == "" {
+		return nil, errEmptyAccessToken
+	}
+
+	return &tokenResp, nil
+}
@@ -0,0 +1,131 @@
+package gopensky_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Auth", func() {
+	Describe("ClientCredentialsAuth", func() {
+		var (
+			tokenRequests atomic.Int32
+			tokenServer   *httptest.Server
+		)
+
+		BeforeEach(func() {
+			tokenRequests.Store(0)
+
+			tokenServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(r.ParseForm()).To(Succeed())
+
+				if r.PostForm.Get("client_secret") != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"error":"invalid_client"}`))
+
+					return
+				}
+
+				Expect(r.PostForm.Get("grant_type")).To(Equal("client_credentials"))
+				Expect(r.PostForm.Get("client_id")).To(Equal("client"))
+
+				count := tokenRequests.Add(1)
+				fmt.Fprintf(w, `{"access_token":"token%d","token_type":"Bearer","expires_in":1800}`, count)
+			}))
+		})
+
+		AfterEach(func() {
+			tokenServer.Close()
+		})
+
+		It("fetches, caches and refreshes the token", func() {
+			now := time.Unix(1696755342, 0)
+			auth := gopensky.NewClientCredentialsAuth("client", "secret", tokenServer.URL, nil)
+			gopensky.SetClientCredentialsNow(auth, func() time.Time { return now })
+
+			token, err := auth.Token(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("token1"))
+
+			now = now.Add(20 * time.Minute)
+			token, err = auth.Token(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("token1"))
+
+			// within the refresh margin
+			now = now.Add(9*time.Minute + 31*time.Second)
+			token, err = auth.Token(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("token2"))
+
+			auth.Invalidate()
+			token, err = auth.Token(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("token3"))
+		})
+
+		It("returns token request errors", func() {
+			auth := gopensky.NewClientCredentialsAuth("client", "invalid", tokenServer.URL, nil)
+
+			_, err := auth.Token(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("oauth2 token request failed"))
+			Expect(err.Error()).To(ContainSubstring("invalid_client"))
+		})
+
+		It("retries once with a new token on unauthorized response", func() {
+			var authHeaders []string
+
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+
+				if r.Header.Get("Authorization") == "Bearer token1" {
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				w.Write([]byte(`{"time": 1, "states": []}`))
+			}))
+			defer apiServer.Close()
+
+			conn, err := gopensky.NewConnection(context.Background(), "", "",
+				gopensky.WithBaseURL(apiServer.URL),
+				gopensky.WithAuthenticator(gopensky.NewClientCredentialsAuth("client", "secret", tokenServer.URL, nil)),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authHeaders).To(Equal([]string{"Bearer token1", "Bearer token2"}))
+
+			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authHeaders).To(HaveLen(3))
+			Expect(tokenRequests.Load()).To(Equal(int32(2)))
+		})
+	})
+
+	Describe("BasicAuth", func() {
+		It("adds the basic authorization header", func() {
+			req, err := http.NewRequest(http.MethodGet, gopensky.OpenSkyAPIURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gopensky.NewBasicAuth("user", "pass").Authenticate(context.Background(), req)).To(Succeed())
+
+			username, password, ok := req.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("user"))
+			Expect(password).To(Equal("pass"))
+		})
+	})
+})
@@ -0,0 +1,153 @@
+package gopensky
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	maxLatitude  = 90.0
+	maxLongitude = 180.0
+)
+
+// Validate checks the bounding box coordinates ranges and the latitude bounds order.
+// The box crosses the antimeridian if Lomin is greater than Lomax, the states queries split it in two boxes.
+// It returns a *BoundingBoxError which matches ErrInvalidBoundingBox.
+func (b *BoundingBoxOptions) Validate() error {
+	for _, latitude := range []struct {
+		field string
+		value float64
+	}{{"lamin", b.Lamin}, {"lamax", b.Lamax}} {
+		if !(latitude.value >= -maxLatitude && latitude.value <= maxLatitude) {
+			return &BoundingBoxError{Field: latitude.field, Value: latitude.value, Err: errBBoxLatitudeRange}
+		}
+	}
+
+	for _, longitude := range []struct {
+		field string
+		value float64
+	}{{"lomin", b.Lomin}, {"lomax", b.Lomax}} {
+		if !(longitude.value >= -maxLongitude && longitude.value <= maxLongitude) {
+			return &BoundingBoxError{Field: longitude.field, Value: longitude.value, Err: errBBoxLongitudeRange}
+		}
+	}
+
+	if b.Lamin > b.Lamax {
+		return &BoundingBoxError{Field: "lamin", Value: b.Lamin, Err: errBBoxLatitudeOrder}
+	}
+
+	return nil
+}
+
+// CrossesAntimeridian returns true if the box crosses the ±180° longitude (Lomin > Lomax).
+func (b *BoundingBoxOptions) CrossesAntimeridian() bool {
+	return b.Lomin > b.Lomax
+}
+
+// Split returns the box, or the two boxes on each side of the antimeridian if the box crosses it.
+func (b *BoundingBoxOptions) Split() []BoundingBoxOptions {
+	if !b.CrossesAntimeridian() {
+		return []BoundingBoxOptions{*b}
+	}
+
+	return []BoundingBoxOptions{
+		{Lamin: b.Lamin, Lomin: b.Lomin, Lamax: b.Lamax, Lomax: maxLongitude},
+		{Lamin: b.Lamin, Lomin: -maxLongitude, Lamax: b.Lamax, Lomax: b.Lomax},
+	}
+}
+
+// String returns the box as [lamin, lomin, lamax, lomax].
+func (b *BoundingBoxOptions) String() string {
+	return fmt.Sprintf("[%g, %g, %g, %g]", b.Lamin, b.Lomin, b.Lamax, b.Lomax)
+}
+
+// GetStatesInRegions retrieves the state vectors within several bounding boxes for a given time.
+// If time = 0 the most recent ones are taken. The boxes crossing the antimeridian are split,
+// one /states/all request is sent per box with bounded concurrency (see WithRangeConcurrency),
+// and the state vectors are merged by ICAO24 address keeping the most recent contact.
+func GetStatesInRegions(ctx context.Context, time int64, icao24 []string, bBoxes []BoundingBoxOptions,
+	extended bool,
+) (*States, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetStatesInRegions(ctx, time, icao24, bBoxes, extended)
+}
+
+// GetStatesInRegions retrieves the state vectors within several bounding boxes for a given time.
+// If time = 0 the most recent ones are taken. The boxes crossing the antimeridian are split,
+// one /states/all request is sent per box with bounded concurrency (see WithRangeConcurrency),
+// and the state vectors are merged by ICAO24 address keeping the most recent contact.
+func (c *Client) GetStatesInRegions(ctx context.Context, time int64, icao24 []string, bBoxes []BoundingBoxOptions,
+	extended bool,
+) (*States, error) {
+	if time < 0 {
+		return nil, ErrInvalidUnixTime
+	}
+
+	if len(bBoxes) == 0 {
+		return nil, fmt.Errorf("%w: no region", ErrInvalidBoundingBox)
+	}
+
+	icao24, err := normalizeICAO24(icao24)
+	if err != nil {
+		return nil, err
+	}
+
+	var regions []BoundingBoxOptions
+
+	for index := range bBoxes {
+		if err := bBoxes[index].Validate(); err != nil {
+			return nil, fmt.Errorf("region %d: %w", index, err)
+		}
+
+		regions = append(regions, bBoxes[index].Split()...)
+	}
+
+	results, err := fetchAll(ctx, len(regions), c.conn.rangeWorkers, func(ctx context.Context, index int,
+	) (*States, error) {
+		requestParams := getStateRequestParams(time, icao24, &regions[index], extended)
+
+		states, err := c.getStates(ctx, "GetStates", "/states/all", requestParams)
+		if err != nil {
+			return nil, fmt.Errorf("states %s: %w", &regions[index], err)
+		}
+
+		return states, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeStates(results), nil
+}
+
+// mergeStates merges the states by ICAO24 address, the state vector with the most recent contact is kept.
+// The state vectors are in order of first appearance and the time is the most recent one.
+func mergeStates(results []*States) *States {
+	merged := States{States: make([]StateVector, 0)}
+	indexes := make(map[string]int)
+
+	for _, states := range results {
+		merged.Time = max(merged.Time, states.Time)
+		merged.Extended = merged.Extended || states.Extended
+
+		for _, stvec := range states.States {
+			index, found := indexes[stvec.Icao24]
+			if !found {
+				indexes[stvec.Icao24] = len(merged.States)
+				merged.States = append(merged.States, stvec)
+
+				continue
+			}
+
+			if stvec.LastContact > merged.States[index].LastContact {
+				merged.States[index] = stvec
+			}
+		}
+	}
+
+	return &merged
+}
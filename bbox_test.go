@@ -0,0 +1,250 @@
+package gopensky_test
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/h2non/gock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+const (
+	regionStatesA = `{"time": 1696755342, "states": [
+		["aaaaa1", "A1", "United States", 1696755342, 1696755340, -75.0, 40.0, 1000.0, false, 200.0,
+		 90.0, 0.0, null, 1100.0, null, false, 0],
+		["aaaaa2", "A2", "United States", 1696755342, 1696755330, -75.5, 40.5, 1000.0, false, 200.0,
+		 90.0, 0.0, null, 1100.0, null, false, 0]]}`
+	regionStatesB = `{"time": 1696755345, "states": [
+		["aaaaa2", "A2", "United States", 1696755345, 1696755344, -75.4, 40.5, 1000.0, false, 200.0,
+		 90.0, 0.0, null, 1100.0, null, false, 0],
+		["aaaaa3", "A3", "United States", 1696755345, 1696755344, -74.0, 41.0, 1000.0, false, 200.0,
+		 90.0, 0.0, null, 1100.0, null, false, 0]]}`
+)
+
+var _ = Describe("Bounding box", func() {
+	It("validates the coordinates", func() {
+		Expect(gopensky.NewBoundingBox(40, -75, 41, -74).Validate()).To(Succeed())
+		Expect(gopensky.NewBoundingBox(-90, -180, 90, 180).Validate()).To(Succeed())
+		Expect(gopensky.NewBoundingBox(50, 170, 60, -170).Validate()).To(Succeed())
+
+		tests := []struct {
+			bBox  *gopensky.BoundingBoxOptions
+			field string
+			error string
+		}{
+			{gopensky.NewBoundingBox(-91, 0, 10, 10), "lamin", "invalid bounding box lamin -91: latitude out of range [-90, 90]"},
+			{gopensky.NewBoundingBox(0, 0, 90.5, 10), "lamax", "invalid bounding box lamax 90.5: latitude out of range [-90, 90]"},
+			{gopensky.NewBoundingBox(0, -181, 10, 10), "lomin", "invalid bounding box lomin -181: longitude out of range [-180, 180]"},
+			{gopensky.NewBoundingBox(0, 0, 10, 200), "lomax", "invalid bounding box lomax 200: longitude out of range [-180, 180]"},
+			{gopensky.NewBoundingBox(10, 0, 0, 10), "lamin", "invalid bounding box lamin 10: lower latitude bound greater than upper bound"},
+			{gopensky.NewBoundingBox(math.NaN(), 0, 0, 10), "lamin", "invalid bounding box lamin NaN: latitude out of range [-90, 90]"},
+		}
+
+		for _, test := range tests {
+			err := test.bBox.Validate()
+			Expect(err).To(MatchError(gopensky.ErrInvalidBoundingBox))
+			Expect(err.Error()).To(Equal(test.error))
+
+			var bBoxErr *gopensky.BoundingBoxError
+			Expect(errors.As(err, &bBoxErr)).To(BeTrue())
+			Expect(bBoxErr.Field).To(Equal(test.field))
+		}
+	})
+
+	It("splits the boxes crossing the antimeridian", func() {
+		bBox := gopensky.NewBoundingBox(40, -75, 41, -74)
+		Expect(bBox.CrossesAntimeridian()).To(BeFalse())
+		Expect(bBox.Split()).To(Equal([]gopensky.BoundingBoxOptions{*bBox}))
+
+		bBox = gopensky.NewBoundingBox(50, 170, 60, -170)
+		Expect(bBox.CrossesAntimeridian()).To(BeTrue())
+		Expect(bBox.Split()).To(Equal([]gopensky.BoundingBoxOptions{
+			{Lamin: 50, Lomin: 170, Lamax: 60, Lomax: 180},
+			{Lamin: 50, Lomin: -180, Lamax: 60, Lomax: -170},
+		}))
+		Expect(bBox.String()).To(Equal("[50, 170, 60, -170]"))
+	})
+
+	Describe("GetStatesInRegions", func() {
+		It("merges the regions states by icao24", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer gock.Off()
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^-76\.0`).
+				Reply(200).
+				BodyString(regionStatesA)
+
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^-75\.5`).
+				Reply(200).
+				BodyString(regionStatesB)
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			gock.InterceptClient(gclient)
+
+			states, err := gopensky.GetStatesInRegions(conn, 0, nil, []gopensky.BoundingBoxOptions{
+				{Lamin: 39, Lomin: -76, Lamax: 41, Lomax: -75},
+				{Lamin: 40, Lomin: -75.5, Lamax: 42, Lomax: -73},
+			}, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gock.IsDone()).To(BeTrue())
+			Expect(states.Time).To(Equal(int64(1696755345)))
+			Expect(states.States).To(HaveLen(3))
+			Expect(states.States[0].Icao24).To(Equal("aaaaa1"))
+			Expect(states.States[1].Icao24).To(Equal("aaaaa2"))
+			Expect(states.States[1].LastContact).To(Equal(int64(1696755344)))
+			Expect(states.States[2].Icao24).To(Equal("aaaaa3"))
+		})
+
+		It("splits the regions crossing the antimeridian", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer gock.Off()
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^170\.0`).
+				MatchParam("lomax", `^180\.0`).
+				Reply(200).
+				BodyString(regionStatesA)
+
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^-180\.0`).
+				MatchParam("lomax", `^-170\.0`).
+				Reply(200).
+				BodyString(`{"time": 1696755342, "states": null}`)
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			gock.InterceptClient(gclient)
+
+			states, err := gopensky.GetStates(conn, 0, nil, gopensky.NewBoundingBox(50, 170, 60, -170), false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gock.IsDone()).To(BeTrue())
+			Expect(states.States).To(HaveLen(2))
+		})
+
+		It("tests GetStatesInRegions errors", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer gock.Off()
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			gock.InterceptClient(gclient)
+
+			_, err = gopensky.GetStatesInRegions(context.Background(), 0, nil, nil, false)
+			Expect(err.Error()).To(ContainSubstring("invalid context key"))
+
+			_, err = gopensky.GetStatesInRegions(conn, -1, nil, nil, false)
+			Expect(err).To(Equal(gopensky.ErrInvalidUnixTime))
+
+			_, err = gopensky.GetStatesInRegions(conn, 0, nil, nil, false)
+			Expect(err).To(MatchError(gopensky.ErrInvalidBoundingBox))
+
+			_, err = gopensky.GetStatesInRegions(conn, 0, []string{"a835a"},
+				[]gopensky.BoundingBoxOptions{{Lamin: 39, Lomin: -76, Lamax: 41, Lomax: -75}}, false)
+			Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+
+			_, err = gopensky.GetStatesInRegions(conn, 0, nil, []gopensky.BoundingBoxOptions{
+				{Lamin: 39, Lomin: -76, Lamax: 41, Lomax: -75},
+				{Lamin: 39, Lomin: -76, Lamax: 91, Lomax: -75},
+			}, false)
+			Expect(err).To(MatchError(gopensky.ErrInvalidBoundingBox))
+			Expect(err.Error()).To(HavePrefix("region 1: invalid bounding box lamax 91"))
+
+			_, err = gopensky.GetStates(conn, 0, nil, gopensky.NewBoundingBox(41, -76, 39, -75), false)
+			Expect(err).To(MatchError(gopensky.ErrInvalidBoundingBox))
+
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				Reply(500)
+
+			_, err = gopensky.GetStatesInRegions(conn, 0, nil,
+				[]gopensky.BoundingBoxOptions{{Lamin: 39, Lomin: -76, Lamax: 41, Lomax: -75}}, false)
+			Expect(err).To(MatchError(gopensky.ErrServerUnavailable))
+			Expect(err.Error()).To(HavePrefix("states [39, -76, 41, -75]: "))
+		})
+	})
+
+	Describe("StreamStates", func() {
+		It("streams the regions crossing the antimeridian", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer gock.Off()
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^170\.0`).
+				Reply(200).
+				BodyString(regionStatesA)
+
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^-180\.0`).
+				Reply(200).
+				BodyString(regionStatesB)
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			gock.InterceptClient(gclient)
+
+			var icao24 []string
+
+			bBox := gopensky.NewBoundingBox(50, 170, 60, -170)
+			for stvec, err := range gopensky.StreamStates(conn, 0, nil, bBox, false) {
+				Expect(err).NotTo(HaveOccurred())
+
+				icao24 = append(icao24, stvec.Icao24)
+			}
+
+			// aaaaa2 is returned by both regions and yielded once.
+			Expect(icao24).To(Equal([]string{"aaaaa1", "aaaaa2", "aaaaa3"}))
+
+			for _, err := range gopensky.StreamStates(conn, 0, nil, gopensky.NewBoundingBox(0, 0, 0, 190), false) {
+				Expect(err).To(MatchError(gopensky.ErrInvalidBoundingBox))
+			}
+		})
+
+		It("stops streaming the regions when the consumer stops", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer gock.Off()
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^170\.0`).
+				Reply(200).
+				BodyString(regionStatesA)
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			gock.InterceptClient(gclient)
+
+			count := 0
+
+			bBox := gopensky.NewBoundingBox(50, 170, 60, -170)
+			for _, err := range gopensky.StreamStates(conn, 0, nil, bBox, false) {
+				Expect(err).NotTo(HaveOccurred())
+
+				count++
+
+				break
+			}
+
+			Expect(count).To(Equal(1))
+			Expect(gock.IsDone()).To(BeTrue())
+		})
+	})
+})
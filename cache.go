@@ -0,0 +1,378 @@
+package gopensky
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheLiveTTL       = 5 * time.Second
+	defaultCacheHistoricalTTL = 24 * time.Hour
+	defaultCacheRecentTTL     = time.Minute
+	defaultCacheHistoricalAge = 24 * time.Hour
+
+	cacheBypassKey     = valueKey("CacheBypass")
+	cacheIdentityBytes = 8
+	fileCacheHeader    = 8
+	fileCacheDirPerm   = 0o700
+
+	fileCachePruneInterval = 10 * time.Minute
+	fileCacheTmpPattern    = "tmp-*"
+	fileCacheStaleTmpAge   = time.Hour
+)
+
+// Cache stores the successful API responses bodies.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached data of the key if it exists and is not expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores the data of the key for the ttl duration.
+	Set(key string, data []byte, ttl time.Duration)
+}
+
+// CachePolicy sets the time to live of the cached responses.
+// A zero TTL disables the cache for the related responses.
+type CachePolicy struct {
+	// TTL of the most recent state vectors (time = 0) and live tracks responses.
+	LiveTTL time.Duration
+
+	// TTL of the queries which ended at least HistoricalAge ago (e.g. flights of a past window).
+	HistoricalTTL time.Duration
+
+	// TTL of the other responses (e.g. queries ending in the last day).
+	RecentTTL time.Duration
+
+	// Age of the query end time after which the OpenSky data is considered final.
+	HistoricalAge time.Duration
+}
+
+// DefaultCachePolicy returns a cache policy with 5s live, 1m recent and 24h historical TTLs,
+// the queries are considered historical one day after their end time.
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		LiveTTL:       defaultCacheLiveTTL,
+		HistoricalTTL: defaultCacheHistoricalTTL,
+		RecentTTL:     defaultCacheRecentTTL,
+		HistoricalAge: defaultCacheHistoricalAge,
+	}
+}
+
+// WithCache sets the response cache of the connection with the given policy.
+func WithCache(cache Cache, policy CachePolicy) Option {
+	return func(c *Connection) error {
+		c.cache = cache
+		c.cachePolicy = policy
+
+		return nil
+	}
+}
+
+// WithoutCache returns a context which bypasses the connection cache for the calls made with it.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey).(bool)
+
+	return bypass
+}
+
+// cacheKey returns the cache key of the request, the identity separates the responses of the accounts.
+func cacheKey(baseURL *url.URL, identity string, endpoint string, queryParams url.Values) string {
+	key := baseURL.JoinPath(endpoint).String() + "?" + queryParams.Encode()
+	if identity != "" {
+		key += "#" + identity
+	}
+
+	return key
+}
+
+// cacheIdentity returns the hashed account identity of the connection cache keys, empty if anonymous.
+// An authenticator which doesn't implement Identifier gets an identity unique to the connection.
+func (c *Connection) cacheIdentity() string {
+	if c.authenticator == nil {
+		return ""
+	}
+
+	identity := fmt.Sprintf("%T@%p", c.authenticator, c)
+	if identifier, ok := c.authenticator.(Identifier); ok {
+		identity = fmt.Sprintf("%T:%s", c.authenticator, identifier.Identity())
+	}
+
+	sum := sha256.Sum256([]byte(identity))
+
+	return hex.EncodeToString(sum[:cacheIdentityBytes])
+}
+
+// ttl returns the cache TTL of the request.
+func (p *CachePolicy) ttl(queryParams url.Values, now time.Time) time.Duration {
+	// states and tracks queries are for a point in time, flights for an interval [begin, end].
+	queryEnd := queryParams.Get("end")
+	if queryEnd == "" {
+		queryEnd = queryParams.Get("time")
+	}
+
+	end, err := strconv.ParseInt(queryEnd, 10, 64)
+	if err != nil || end <= 0 {
+		return p.LiveTTL
+	}
+
+	if now.Sub(time.Unix(end, 0)) >= p.HistoricalAge {
+		return p.HistoricalTTL
+	}
+
+	return p.RecentTTL
+}
+
+// MemoryCache is an in-memory least recently used (LRU) cache.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	now        func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key     string
+	data    []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns an in-memory LRU cache holding up to maxEntries responses,
+// the cache is unbounded if maxEntries <= 0.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+// Get returns the cached data of the key if it exists and is not expired.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry, _ := element.Value.(*memoryCacheEntry)
+	if !m.now().Before(entry.expires) {
+		m.remove(element)
+
+		return nil, false
+	}
+
+	m.lru.MoveToFront(element)
+
+	return entry.data, true
+}
+
+// Set stores the data of the key for the ttl duration, the least recently used entry is evicted if full.
+func (m *MemoryCache) Set(key string, data []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryCacheEntry{key: key, data: data, expires: m.now().Add(ttl)}
+
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.lru.MoveToFront(element)
+
+		return
+	}
+
+	m.entries[key] = m.lru.PushFront(entry)
+
+	for m.maxEntries > 0 && m.lru.Len() > m.maxEntries {
+		m.remove(m.lru.Back())
+	}
+}
+
+// Len returns the number of cached entries, including the expired ones not evicted yet.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.lru.Len()
+}
+
+func (m *MemoryCache) remove(element *list.Element) {
+	entry, _ := m.lru.Remove(element).(*memoryCacheEntry)
+	delete(m.entries, entry.key)
+}
+
+// FileCache is a file system cache, each response is stored in a file of the cache directory.
+// The expired files are removed by Prune, which Set runs every 10 minutes.
+type FileCache struct {
+	dir string
+	now func() time.Time
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewFileCache returns a file system cache in the given directory, the directory is created if not exists.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, fileCacheDirPerm); err != nil {
+		return nil, fmt.Errorf("cache directory: %w", err)
+	}
+
+	return &FileCache{dir: dir, now: time.Now}, nil
+}
+
+// Get returns the cached data of the key if it exists and is not expired.
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	path := f.path(key)
+
+	content, err := os.ReadFile(path)
+	if err != nil || len(content) < fileCacheHeader {
+		return nil, false
+	}
+
+	// the expired file is left to Prune, a concurrent Set may be replacing it.
+	expires := time.Unix(0, int64(binary.BigEndian.Uint64(content[:fileCacheHeader]))) //nolint:gosec
+	if !f.now().Before(expires) {
+		return nil, false
+	}
+
+	return content[fileCacheHeader:], true
+}
+
+// Set stores the data of the key for the ttl duration.
+// The cache is best effort, write errors are ignored.
+func (f *FileCache) Set(key string, data []byte, ttl time.Duration) {
+	f.pruneIfDue()
+
+	content := make([]byte, fileCacheHeader, fileCacheHeader+len(data))
+	binary.BigEndian.PutUint64(content, uint64(f.now().Add(ttl).UnixNano())) //nolint:gosec
+	content = append(content, data...)
+
+	// write to a temporary file and rename it, so readers never see a partial file.
+	tmpFile, err := os.CreateTemp(f.dir, fileCacheTmpPattern)
+	if err != nil {
+		return
+	}
+
+	_, err = tmpFile.Write(content)
+	err = errors.Join(err, tmpFile.Close())
+
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), f.path(key))
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+	}
+}
+
+// Prune removes the expired entries files of the cache directory, and the temporary files older than
+// one hour left by an interrupted Set. It returns the number of removed files.
+func (f *FileCache) Prune() int {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	now := f.now()
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		path := filepath.Join(f.dir, entry.Name())
+
+		var stale bool
+
+		switch matched, _ := filepath.Match(fileCacheTmpPattern, entry.Name()); {
+		case matched:
+			stale = isStaleTmpFile(entry, now)
+		case len(entry.Name()) == hex.EncodedLen(sha256.Size):
+			stale = isExpiredFile(path, now)
+		}
+
+		if stale && os.Remove(path) == nil {
+			removed++
+		}
+	}
+
+	return removed
+}
+
+func isStaleTmpFile(entry os.DirEntry, now time.Time) bool {
+	info, err := entry.Info()
+
+	return err == nil && now.Sub(info.ModTime()) >= fileCacheStaleTmpAge
+}
+
+// isExpiredFile returns true if the cache file is expired (or invalid),
+// and it is still the same file (not replaced by a concurrent Set).
+func isExpiredFile(path string, now time.Time) bool {
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return false
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	header := make([]byte, fileCacheHeader)
+	if _, err := io.ReadFull(file, header); err == nil {
+		expires := time.Unix(0, int64(binary.BigEndian.Uint64(header))) //nolint:gosec
+		if now.Before(expires) {
+			return false
+		}
+	}
+
+	current, err := os.Stat(path)
+
+	return err == nil && os.SameFile(info, current)
+}
+
+// pruneIfDue runs Prune if the last one is older than the prune interval.
+func (f *FileCache) pruneIfDue() {
+	f.mu.Lock()
+
+	now := f.now()
+	due := now.Sub(f.lastPrune) >= fileCachePruneInterval
+
+	if due {
+		f.lastPrune = now
+	}
+
+	f.mu.Unlock()
+
+	if due {
+		f.Prune()
+	}
+}
+
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,252 @@
+package gopensky_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+// tokenAuth is an authenticator without identity.
+type tokenAuth struct{}
+
+func (a *tokenAuth) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer token")
+
+	return nil
+}
+
+var _ = Describe("Cache", func() {
+	Describe("MemoryCache", func() {
+		It("evicts the least recently used entry", func() {
+			cache := gopensky.NewMemoryCache(2)
+
+			cache.Set("a", []byte("1"), time.Minute)
+			cache.Set("b", []byte("2"), time.Minute)
+
+			_, ok := cache.Get("a")
+			Expect(ok).To(BeTrue())
+
+			cache.Set("c", []byte("3"), time.Minute)
+			Expect(cache.Len()).To(Equal(2))
+
+			_, ok = cache.Get("b")
+			Expect(ok).To(BeFalse())
+
+			data, ok := cache.Get("a")
+			Expect(ok).To(BeTrue())
+			Expect(data).To(Equal([]byte("1")))
+		})
+
+		It("expires the entries", func() {
+			now := time.Unix(1696755342, 0)
+			cache := gopensky.NewMemoryCache(0)
+			gopensky.SetMemoryCacheNow(cache, func() time.Time { return now })
+
+			cache.Set("a", []byte("1"), time.Minute)
+
+			now = now.Add(time.Minute)
+
+			_, ok := cache.Get("a")
+			Expect(ok).To(BeFalse())
+			Expect(cache.Len()).To(Equal(0))
+		})
+	})
+
+	Describe("FileCache", func() {
+		It("stores and expires the entries", func() {
+			now := time.Unix(1696755342, 0)
+
+			cache, err := gopensky.NewFileCache(GinkgoT().TempDir())
+			Expect(err).NotTo(HaveOccurred())
+			gopensky.SetFileCacheNow(cache, func() time.Time { return now })
+
+			_, ok := cache.Get("a")
+			Expect(ok).To(BeFalse())
+
+			cache.Set("a", []byte(`{"time": 1}`), time.Hour)
+
+			data, ok := cache.Get("a")
+			Expect(ok).To(BeTrue())
+			Expect(data).To(Equal([]byte(`{"time": 1}`)))
+
+			now = now.Add(time.Hour)
+
+			_, ok = cache.Get("a")
+			Expect(ok).To(BeFalse())
+
+			// the expired file is left to Prune.
+			cache.Set("a", []byte(`{"time": 2}`), time.Hour)
+
+			data, ok = cache.Get("a")
+			Expect(ok).To(BeTrue())
+			Expect(data).To(Equal([]byte(`{"time": 2}`)))
+		})
+
+		It("prunes the expired entries files", func() {
+			now := time.Unix(1696755342, 0)
+			dir := GinkgoT().TempDir()
+
+			cache, err := gopensky.NewFileCache(dir)
+			Expect(err).NotTo(HaveOccurred())
+			gopensky.SetFileCacheNow(cache, func() time.Time { return now })
+
+			cache.Set("a", []byte("1"), time.Minute)
+			cache.Set("b", []byte("2"), time.Hour)
+			Expect(os.ReadDir(dir)).To(HaveLen(2))
+
+			// the temporary files left by an interrupted Set are removed after one hour.
+			for name, age := range map[string]time.Duration{"tmp-stale": 2 * time.Hour, "tmp-writing": time.Second} {
+				path := filepath.Join(dir, name)
+				Expect(os.WriteFile(path, []byte("partial"), 0o600)).To(Succeed())
+				Expect(os.Chtimes(path, now.Add(-age), now.Add(-age))).To(Succeed())
+			}
+
+			now = now.Add(2 * time.Minute)
+			Expect(cache.Prune()).To(Equal(2))
+			Expect(os.ReadDir(dir)).To(HaveLen(2))
+			Expect(os.Remove(filepath.Join(dir, "tmp-writing"))).To(Succeed())
+
+			// Set prunes the directory every 10 minutes.
+			now = now.Add(time.Hour)
+			cache.Set("c", []byte("3"), time.Hour)
+			Expect(os.ReadDir(dir)).To(HaveLen(1))
+
+			data, ok := cache.Get("c")
+			Expect(ok).To(BeTrue())
+			Expect(data).To(Equal([]byte("3")))
+		})
+	})
+
+	Describe("CachePolicy", func() {
+		policy := gopensky.DefaultCachePolicy()
+		now := time.Unix(1696755342, 0)
+
+		It("uses the live TTL for the most recent states", func() {
+			ttl := gopensky.CachePolicyTTL(policy, url.Values{"time": []string{"0"}}, now)
+			Expect(ttl).To(Equal(policy.LiveTTL))
+
+			ttl = gopensky.CachePolicyTTL(policy, url.Values{}, now)
+			Expect(ttl).To(Equal(policy.LiveTTL))
+		})
+
+		It("uses the historical TTL for the past queries", func() {
+			params := url.Values{"begin": []string{"1696000000"}, "end": []string{"1696003600"}}
+			Expect(gopensky.CachePolicyTTL(policy, params, now)).To(Equal(policy.HistoricalTTL))
+
+			params = url.Values{"time": []string{"1696755000"}}
+			Expect(gopensky.CachePolicyTTL(policy, params, now)).To(Equal(policy.RecentTTL))
+		})
+	})
+
+	Describe("WithCache", func() {
+		var (
+			server   *httptest.Server
+			requests int
+		)
+
+		BeforeEach(func() {
+			requests = 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests++
+				w.Write([]byte(`[{"icao24": "c060b9", "firstSeen": 1696000000, "lastSeen": 1696003000}]`))
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("serves the repeated historical queries from the cache", func() {
+			instrumenter := &testInstrumenter{}
+
+			client, err := gopensky.NewClient("", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithInstrumenter(instrumenter),
+				gopensky.WithCache(gopensky.NewMemoryCache(10), gopensky.DefaultCachePolicy()),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			for range 2 {
+				flights, err := client.GetFlightsByAircraft(context.Background(), "c060b9", 1695900000, 1696100000)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(flights).To(HaveLen(1))
+			}
+
+			Expect(requests).To(Equal(1))
+			Expect(instrumenter.results).To(HaveLen(2))
+			Expect(instrumenter.results[0].Cached).To(BeFalse())
+			Expect(instrumenter.results[1].Cached).To(BeTrue())
+
+			_, err = client.GetFlightsByAircraft(gopensky.WithoutCache(context.Background()),
+				"c060b9", 1695900000, 1696100000)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests).To(Equal(2))
+		})
+
+		It("doesn't share the cached responses between accounts", func() {
+			cache := gopensky.NewMemoryCache(10)
+
+			newClient := func(username string, opts ...gopensky.Option) *gopensky.Client {
+				opts = append(opts,
+					gopensky.WithBaseURL(server.URL),
+					gopensky.WithCache(cache, gopensky.DefaultCachePolicy()),
+				)
+
+				client, err := gopensky.NewClient(username, "secret", opts...)
+				Expect(err).NotTo(HaveOccurred())
+
+				return client
+			}
+
+			clients := []*gopensky.Client{
+				newClient(""),
+				newClient("alice"),
+				newClient("bob"),
+				newClient("", gopensky.WithAuthenticator(&tokenAuth{})),
+				newClient("", gopensky.WithAuthenticator(&tokenAuth{})),
+				newClient("alice"),
+			}
+
+			for _, client := range clients {
+				_, err := client.GetFlightsByAircraft(context.Background(), "c060b9", 1695900000, 1696100000)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			// the authenticators without identity don't share the cache, the second alice client does.
+			Expect(requests).To(Equal(5))
+			Expect(cache.Len()).To(Equal(5))
+		})
+
+		It("doesn't cache the error responses", func() {
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			cache := gopensky.NewMemoryCache(10)
+
+			client, err := gopensky.NewClient("", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithCache(cache, gopensky.DefaultCachePolicy()),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			for range 2 {
+				_, err = client.GetFlightsByAircraft(context.Background(), "c060b9", 1695900000, 1696100000)
+				Expect(err).To(MatchError(gopensky.ErrNotFound))
+			}
+
+			Expect(requests).To(Equal(2))
+			Expect(cache.Len()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,190 @@
+package gopensky
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	airlineDesignatorLength = 3
+	maxIATAFlightNumber     = 4
+)
+
+//go:embed data/airlines.csv
+var airlinesCSV []byte
+
+var airlines = sync.OnceValue(func() map[string]Airline { //nolint:gochecknoglobals
+	airlines, err := parseAirlines(airlinesCSV)
+	if err != nil {
+		panic(err)
+	}
+
+	return airlines
+})
+
+// Airline is an aircraft operator identified by its ICAO designator.
+type Airline struct {
+	// ICAO airline designator (3 letters), e.g. AAL.
+	ICAO string `json:"icao"`
+
+	// IATA airline designator (2 characters), e.g. AA. Empty if the airline has no IATA designator.
+	IATA string `json:"iata"`
+
+	// Airline name.
+	Name string `json:"name"`
+
+	// Country of the airline.
+	Country string `json:"country"`
+}
+
+// Callsign is a parsed callsign, the API callsigns are padded to 8 characters.
+type Callsign struct {
+	// Callsign without the padding, e.g. AAL2423.
+	Value string `json:"value"`
+
+	// ICAO airline designator, empty if the callsign is not an airline flight (e.g. a registration).
+	Designator string `json:"designator"`
+
+	// Flight number following the airline designator, e.g. 2423.
+	FlightNumber string `json:"flightNumber"`
+}
+
+// LookupAirline returns the airline of the ICAO designator from the embedded airlines dataset.
+func LookupAirline(designator string) (Airline, bool) {
+	airline, found := airlines()[strings.ToUpper(designator)]
+
+	return airline, found
+}
+
+// ParseCallsign normalizes the callsign padding and splits the ICAO airline designator from the flight number.
+// The callsign is an airline flight if it starts with three letters followed by a number (e.g. AAL2423).
+func ParseCallsign(callsign string) Callsign {
+	parsed := Callsign{Value: strings.ToUpper(strings.TrimSpace(callsign))}
+
+	if len(parsed.Value) <= airlineDesignatorLength {
+		return parsed
+	}
+
+	for _, char := range parsed.Value[:airlineDesignatorLength] {
+		if char < 'A' || char > 'Z' {
+			return parsed
+		}
+	}
+
+	flightNumber := parsed.Value[airlineDesignatorLength:]
+	if flightNumber[0] < '0' || flightNumber[0] > '9' {
+		return parsed
+	}
+
+	for _, char := range flightNumber {
+		if (char < '0' || char > '9') && (char < 'A' || char > 'Z') {
+			return parsed
+		}
+	}
+
+	parsed.Designator = parsed.Value[:airlineDesignatorLength]
+	parsed.FlightNumber = flightNumber
+
+	return parsed
+}
+
+// String returns the callsign without the padding.
+func (c Callsign) String() string {
+	return c.Value
+}
+
+// IsAirline returns true if the callsign has an ICAO airline designator and a flight number.
+func (c Callsign) IsAirline() bool {
+	return c.Designator != ""
+}
+
+// Airline returns the airline of the callsign designator.
+func (c Callsign) Airline() (Airline, bool) {
+	if !c.IsAirline() {
+		return Airline{}, false
+	}
+
+	return LookupAirline(c.Designator)
+}
+
+// IATA returns the IATA flight number (e.g. AAL2423 is AA2423).
+// The conversion is possible only for known airlines with an IATA designator and numeric flight numbers up to 4 digits.
+func (c Callsign) IATA() (string, bool) {
+	airline, found := c.Airline()
+	if !found || airline.IATA == "" {
+		return "", false
+	}
+
+	flightNumber := strings.TrimLeft(c.FlightNumber, "0")
+	if flightNumber == "" || len(flightNumber) > maxIATAFlightNumber {
+		return "", false
+	}
+
+	for _, char := range flightNumber {
+		if char < '0' || char > '9' {
+			return "", false
+		}
+	}
+
+	return airline.IATA + flightNumber, true
+}
+
+// ParseCallsign parses the state vector callsign, it returns an empty callsign if no callsign has been received.
+func (s *StateVector) ParseCallsign() Callsign {
+	return parseOptionalCallsign(s.Callsign)
+}
+
+// ParseCallsign parses the flight callsign, it returns an empty callsign if the callsign is nil.
+func (f *FlighData) ParseCallsign() Callsign {
+	return parseOptionalCallsign(f.Callsign)
+}
+
+// ParseCallsign parses the track callsign, it returns an empty callsign if the callsign is nil.
+func (t *FlightTrack) ParseCallsign() Callsign {
+	return parseOptionalCallsign(t.Callsign)
+}
+
+// GroupByAirline groups the state vectors by the ICAO airline designator of their callsign.
+// The state vectors without an airline callsign are left out.
+func (s *States) GroupByAirline() map[string][]StateVector {
+	groups := make(map[string][]StateVector)
+
+	for _, stvec := range s.States {
+		callsign := stvec.ParseCallsign()
+		if callsign.IsAirline() {
+			groups[callsign.Designator] = append(groups[callsign.Designator], stvec)
+		}
+	}
+
+	return groups
+}
+
+func parseOptionalCallsign(callsign *string) Callsign {
+	if callsign == nil {
+		return Callsign{}
+	}
+
+	return ParseCallsign(*callsign)
+}
+
+func parseAirlines(data []byte) (map[string]Airline, error) {
+	records, err := readCSVRecords(data)
+	if err != nil {
+		return nil, fmt.Errorf("airlines dataset: %w", err)
+	}
+
+	airlines := make(map[string]Airline, len(records))
+
+	for _, record := range records {
+		airlines[record[0]] = Airline{
+			ICAO:    record[0],
+			IATA:    record[1],
+			Name:    record[2],
+			Country: record[3],
+		}
+	}
+
+	return airlines, nil
+}
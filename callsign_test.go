@@ -0,0 +1,91 @@
+package gopensky_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Callsign", func() {
+	It("parses the airline callsigns", func() {
+		callsign := gopensky.ParseCallsign("AAL2423 ")
+		Expect(callsign).To(Equal(gopensky.Callsign{Value: "AAL2423", Designator: "AAL", FlightNumber: "2423"}))
+		Expect(callsign.String()).To(Equal("AAL2423"))
+		Expect(callsign.IsAirline()).To(BeTrue())
+
+		callsign = gopensky.ParseCallsign(" ezy45mx")
+		Expect(callsign.Designator).To(Equal("EZY"))
+		Expect(callsign.FlightNumber).To(Equal("45MX"))
+
+		for _, value := range []string{"N628TS", "DABCD", "AAL", "GA-BCD", "", "        "} {
+			callsign = gopensky.ParseCallsign(value)
+			Expect(callsign.IsAirline()).To(BeFalse(), value)
+			Expect(callsign.FlightNumber).To(BeEmpty(), value)
+		}
+	})
+
+	It("resolves the airline and the IATA flight number", func() {
+		callsign := gopensky.ParseCallsign("AAL2423 ")
+
+		airline, found := callsign.Airline()
+		Expect(found).To(BeTrue())
+		Expect(airline).To(Equal(gopensky.Airline{
+			ICAO: "AAL", IATA: "AA", Name: "American Airlines", Country: "United States",
+		}))
+
+		iata, found := callsign.IATA()
+		Expect(found).To(BeTrue())
+		Expect(iata).To(Equal("AA2423"))
+
+		iata, found = gopensky.ParseCallsign("DLH0400").IATA()
+		Expect(found).To(BeTrue())
+		Expect(iata).To(Equal("LH400"))
+
+		airline, found = gopensky.LookupAirline("swr")
+		Expect(found).To(BeTrue())
+		Expect(airline.Country).To(Equal("Switzerland"))
+
+		for _, value := range []string{"EZY45MX", "EJA123", "XXX123", "AAL12345", "AAL000", "N628TS"} {
+			_, found = gopensky.ParseCallsign(value).IATA()
+			Expect(found).To(BeFalse(), value)
+		}
+
+		_, found = gopensky.ParseCallsign("XXX123").Airline()
+		Expect(found).To(BeFalse())
+	})
+
+	It("parses the states, flights and tracks callsigns", func() {
+		callsign := "POE2136"
+
+		flight := gopensky.FlighData{Callsign: &callsign}
+		Expect(flight.ParseCallsign().Designator).To(Equal("POE"))
+
+		track := gopensky.FlightTrack{Callsign: &callsign}
+		Expect(track.ParseCallsign().FlightNumber).To(Equal("2136"))
+
+		stvec := gopensky.StateVector{}
+		Expect(stvec.ParseCallsign()).To(Equal(gopensky.Callsign{}))
+	})
+
+	It("groups the states by airline", func() {
+		data, err := os.ReadFile("mock_data/all_states.json")
+		Expect(err).NotTo(HaveOccurred())
+
+		states, err := gopensky.DecodeStatesResponse(data)
+		Expect(err).NotTo(HaveOccurred())
+
+		groups := states.GroupByAirline()
+		Expect(groups).To(HaveKey("AAL"))
+		Expect(groups).To(HaveKey("UAL"))
+		Expect(groups["AAL"]).To(HaveLen(2))
+
+		for designator, group := range groups {
+			for _, stvec := range group {
+				Expect(stvec.ParseCallsign().Designator).To(Equal(designator))
+			}
+		}
+	})
+})
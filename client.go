@@ -0,0 +1,39 @@
+package gopensky
+
+import (
+	"context"
+	"fmt"
+)
+
+// Client is an OpenSky Network API client which holds its own connection.
+// It is an alternative to the connection context built by NewConnection,
+// all package level API functions have a Client method counterpart.
+type Client struct {
+	conn *Connection
+}
+
+// NewClient returns a new OpenSky Network API client.
+// Anonymous connection will be used by providing empty username.
+func NewClient(username string, password string, opts ...Option) (*Client, error) {
+	conn, err := newConnection(username, password, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+// RateLimit returns the API credit status reported by the last API response of the client.
+func (c *Client) RateLimit() RateLimit {
+	return c.conn.rateLimit.get()
+}
+
+// contextClient returns a client for the connection of the context build by NewConnection().
+func contextClient(ctx context.Context) (*Client, error) {
+	conn, err := getClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("client: %w", err)
+	}
+
+	return &Client{conn: conn}, nil
+}
@@ -0,0 +1,97 @@
+package gopensky_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server    *httptest.Server
+		usernames []string
+	)
+
+	BeforeEach(func() {
+		usernames = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, _, _ := r.BasicAuth()
+			usernames = append(usernames, username)
+
+			w.Header().Set("X-Rate-Limit-Remaining", "3990")
+
+			mockFile := map[string]string{
+				"/states/all":        "mock_data/all_states.json",
+				"/flights/arrival":   "mock_data/flights_data.json",
+				"/flights/departure": "mock_data/flights_data.json",
+				"/flights/all":       "mock_data/flights_data.json",
+				"/flights/aircraft":  "mock_data/flights_data.json",
+				"/tracks/all":        "mock_data/all_tracks.json",
+			}[r.URL.Path]
+
+			data, err := os.ReadFile(mockFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			w.Write(data)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewClient", func() {
+		It("calls the API with its own connection", func() {
+			ctx := context.Background()
+
+			client1, err := gopensky.NewClient("user1", "pass1", gopensky.WithBaseURL(server.URL))
+			Expect(err).NotTo(HaveOccurred())
+
+			client2, err := gopensky.NewClient("user2", "pass2", gopensky.WithBaseURL(server.URL))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client1.RateLimit().Remaining).To(Equal(-1))
+
+			states, err := client1.GetStates(ctx, 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(states.States).To(HaveLen(6))
+			Expect(client1.RateLimit().Remaining).To(Equal(3990))
+			Expect(client2.RateLimit().Remaining).To(Equal(-1))
+
+			flights, err := client2.GetArrivalsByAirport(ctx, "KEWR", 1696755342, 1696928142)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flights).To(HaveLen(3))
+
+			flights, err = client1.GetDeparturesByAirport(ctx, "KEWR", 1696755342, 1696928142)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flights).To(HaveLen(3))
+
+			flights, err = client2.GetFlightsByInterval(ctx, 1696755342, 1696758942)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flights).To(HaveLen(3))
+
+			flights, err = client1.GetFlightsByAircraft(ctx, "c060b9", 1696755342, 1696928142)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flights).To(HaveLen(3))
+
+			track, err := client2.GetTrackByAircraft(ctx, "c060b9", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(track.Icao24).To(Equal("c060b9"))
+
+			Expect(usernames).To(Equal([]string{"user1", "user2", "user1", "user2", "user1", "user2"}))
+		})
+
+		It("returns option errors", func() {
+			_, err := gopensky.NewClient("", "", gopensky.WithBaseURL("opensky"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid base url"))
+		})
+	})
+})
@@ -2,18 +2,23 @@ package gopensky
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
 	openSkyAPIURL = "https://opensky-network.org:443/api"
 	clientKey     = valueKey("Client")
+
+	defaultDialTimeout         = 30 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
 )
 
 type valueKey string
@@ -21,20 +26,47 @@ type valueKey string
 type apiResponse struct { //nolint:recvcheck
 	*http.Response
 
-	Request *http.Request
+	Request  *http.Request
+	endpoint string
 }
 
 type Connection struct {
-	auth   string
-	uri    *url.URL
-	client *http.Client
+	authenticator Authenticator
+	uri           *url.URL
+	client        *http.Client
+	transport     http.RoundTripper
+	timeout       *time.Duration
+	userAgent     string
+	retryPolicy   *RetryPolicy
+	rateLimit     *rateLimitTracker
+	budget        *creditBudget
+	middlewares   []Middleware
+	logger        *slog.Logger
+	instrumenter  Instrumenter
+	cache         Cache
+	cachePolicy   CachePolicy
+	rangeWorkers  int
+	sleep         func(ctx context.Context, wait time.Duration) error
 }
 
 func newConnectionError(err error) error {
 	return connectionError{err: err}
 }
 
-func NewConnection(ctx context.Context, username string, password string) (context.Context, error) {
+// NewConnection creates a new connection context to OpenSky Network live API server.
+// Anonymous connection will be used by providing empty username.
+// The options are applied in the given order after the default configuration.
+func NewConnection(ctx context.Context, username string, password string, opts ...Option,
+) (context.Context, error) {
+	connection, err := newConnection(username, password, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return context.WithValue(ctx, clientKey, connection), nil
+}
+
+func newConnection(username string, password string, opts ...Option) (*Connection, error) {
 	_url, err := url.Parse(openSkyAPIURL)
 	if err != nil {
 		perr := fmt.Errorf("invalid url %s: %w", openSkyAPIURL, err)
@@ -43,25 +75,62 @@ func NewConnection(ctx context.Context, username string, password string) (conte
 	}
 
 	connection := Connection{
-		uri: _url,
+		uri:       _url,
+		client:    &http.Client{Transport: newTransport()},
+		rateLimit: newRateLimitTracker(),
+		logger:    slog.New(slog.DiscardHandler),
+		sleep:     sleepContext,
+
+		rangeWorkers: defaultRangeConcurrency,
 	}
 
 	if username != "" {
-		connection.auth = base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		connection.authenticator = NewBasicAuth(username, password)
+	}
+
+	for _, opt := range opts {
+		if err := opt(&connection); err != nil {
+			return nil, newConnectionError(err)
+		}
+	}
+
+	connection.client = connection.httpClient()
+
+	return &connection, nil
+}
+
+// httpClient returns a copy of the connection HTTP client with the transport and timeout options applied,
+// or the client itself if there is none, so that a client given by WithHTTPClient is never modified.
+func (c *Connection) httpClient() *http.Client {
+	if c.transport == nil && c.timeout == nil {
+		return c.client
 	}
 
-	dialContext := func(ctx context.Context, _, _ string) (net.Conn, error) { //nolint:revive
-		return net.Dial("tcp", _url.Host) //nolint:noctx
+	clone := *c.client
+
+	if c.transport != nil {
+		clone.Transport = c.transport
 	}
 
-	connection.client = &http.Client{
-		Transport: &http.Transport{
-			DialContext:        dialContext,
-			DisableCompression: true,
-		},
+	if c.timeout != nil {
+		clone.Timeout = *c.timeout
 	}
 
-	return context.WithValue(ctx, clientKey, &connection), nil
+	return &clone
+}
+
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   defaultDialTimeout,
+		KeepAlive: defaultKeepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: defaultTLSHandshakeTimeout,
+		DisableCompression:  true,
+	}
 }
 
 // getClient from context build by NewConnection().
@@ -73,27 +142,139 @@ func getClient(ctx context.Context) (*Connection, error) {
 	return nil, fmt.Errorf("%w %s", errContextKey, clientKey)
 }
 
-func (c *Connection) doGetRequest(ctx context.Context, endpoint string, queryParams url.Values,
+// getJSON sends the call request and unmarshals the successful response into the given value.
+func (c *Connection) getJSON(ctx context.Context, call *apiCall, unmarshalInto any) error {
+	var key string
+
+	if c.cache != nil && !cacheBypassed(ctx) {
+		key = cacheKey(c.uri, c.cacheIdentity(), call.info.Endpoint, call.info.Params)
+
+		if data, ok := c.cache.Get(key); ok {
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "api cache hit",
+				slog.String("endpoint", call.info.Endpoint),
+				slog.String("params", call.info.Params.Encode()))
+
+			call.result.StatusCode = http.StatusOK
+			call.result.ResponseSize = int64(len(data))
+			call.result.Cached = true
+
+			return call.decode(func() error {
+				return unmarshalData(data, unmarshalInto)
+			})
+		}
+	}
+
+	response, err := c.doGetRequest(ctx, call.info.Endpoint, call.info.Params)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	data, errRead := response.readBody()
+
+	err = response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("response body close %w", err)
+	}
+
+	if errRead != nil {
+		return errRead
+	}
+
+	call.result.StatusCode = response.StatusCode
+	call.result.ResponseSize = int64(len(data))
+
+	err = call.decode(func() error {
+		return response.processData(data, unmarshalInto)
+	})
+	if err != nil {
+		return err
+	}
+
+	if key != "" && response.isSuccess() {
+		if ttl := c.cachePolicy.ttl(call.info.Params, time.Now()); ttl > 0 {
+			c.cache.Set(key, data, ttl)
+		}
+	}
+
+	return nil
+}
+
+// doAuthenticatedRequest sends the request and retries it once with a new credential
+// if the API responded with 401 Unauthorized.
+func (c *Connection) doAuthenticatedRequest(ctx context.Context, endpoint string, queryParams url.Values,
+) (*apiResponse, error) {
+	response, err := c.sendRequest(ctx, endpoint, queryParams)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+
+	// the cached credential may have been revoked or expired earlier than announced,
+	// retry once with a fresh one.
+	invalidator, ok := c.authenticator.(Invalidator)
+	if !ok {
+		return response, nil
+	}
+
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "api request unauthorized, retrying with new credential",
+		slog.String("endpoint", endpoint))
+
+	invalidator.Invalidate()
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	return c.sendRequest(ctx, endpoint, queryParams)
+}
+
+func (c *Connection) sendRequest(ctx context.Context, endpoint string, queryParams url.Values,
 ) (*apiResponse, error) {
-	requestURL := fmt.Sprintf("%s/%s", c.uri, endpoint)
+	requestURL := c.uri.JoinPath(endpoint)
 
 	if len(queryParams) > 0 {
-		params := queryParams.Encode()
-		requestURL = fmt.Sprintf("%s?%s", requestURL, params)
+		requestURL.RawQuery = queryParams.Encode()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("new request: %w", err)
 	}
 
-	if c.auth != "" {
-		req.Header.Add("Authorization", "Basic "+c.auth)
+	if c.authenticator != nil {
+		if err := c.authenticator.Authenticate(ctx, req); err != nil {
+			c.logger.LogAttrs(ctx, slog.LevelError, "api request authentication failed",
+				slog.String("endpoint", endpoint),
+				slog.String("authenticator", fmt.Sprintf("%T", c.authenticator)),
+				slog.Any("error", err))
+
+			return nil, fmt.Errorf("authenticate: %w", err)
+		}
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
 
-	response, err := c.client.Do(req) //nolint:bodyclose
+	apiReq := &Request{
+		Endpoint:    endpoint,
+		Params:      queryParams,
+		HTTPRequest: req,
+	}
+
+	start := time.Now()
+
+	response, err := c.roundTrip(apiReq) //nolint:bodyclose
+	if err == nil {
+		remaining := c.rateLimit.update(response.Header, time.Now())
+		if c.budget != nil {
+			c.budget.sync(remaining)
+		}
+	}
 
-	return &apiResponse{response, req}, err //nolint:wrapcheck
+	apiResp := &apiResponse{response, req, endpoint}
+
+	c.logRequest(ctx, apiReq, apiResp, time.Since(start), err)
+
+	return apiResp, err //nolint:wrapcheck
 }
 
 func (h *apiResponse) isInformational() bool {
@@ -117,35 +298,58 @@ func (h apiResponse) process(unmarshalInto any) error {
 // processWithError drains the response body, and processes the HTTP status code
 // Note: Closing the response.Body is left to the caller.
 func (h apiResponse) processWithError(unmarshalInto any) error {
-	data, err := io.ReadAll(h.Body)
+	data, err := h.readBody()
 	if err != nil {
-		return fmt.Errorf("unable to process API response: %w", err)
+		return err
 	}
 
-	if h.isSuccess() || h.isRedirection() {
-		if unmarshalInto != nil {
-			err := json.Unmarshal(data, unmarshalInto)
-			if err != nil {
-				return fmt.Errorf("unmarshalling into %#v, data %q: %w", unmarshalInto, string(data), err)
-			}
+	return h.processData(data, unmarshalInto)
+}
 
-			return nil
-		}
+func (h apiResponse) readBody() ([]byte, error) {
+	data, err := io.ReadAll(h.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to process API response: %w", err)
+	}
 
-		return nil
+	return data, nil
+}
+
+// processData processes the HTTP status code and unmarshals the drained response body.
+func (h apiResponse) processData(data []byte, unmarshalInto any) error {
+	if h.isSuccess() || h.isRedirection() {
+		return unmarshalData(data, unmarshalInto)
 	}
 
 	if h.isInformational() {
 		return nil
 	}
 
-	return handleError(h.StatusCode, data)
+	apiErr := handleError(h.StatusCode, data)
+	apiErr.Endpoint = h.endpoint
+	apiErr.Query = h.Request.URL.Query()
+	apiErr.Header = apiErrorHeader(h.Header)
+
+	return apiErr
 }
 
-func handleError(statusCode int, data []byte) error {
-	errorModel := httpModelError{
-		Message: fmt.Sprintf("%s %s", http.StatusText(statusCode), data),
+// unmarshalData unmarshals the successful response body.
+func unmarshalData(data []byte, unmarshalInto any) error {
+	if unmarshalInto == nil {
+		return nil
+	}
+
+	err := json.Unmarshal(data, unmarshalInto)
+	if err != nil {
+		return fmt.Errorf("unmarshalling into %#v, data %q: %w", unmarshalInto, string(data), err)
 	}
 
-	return errorModel
+	return nil
+}
+
+func handleError(statusCode int, data []byte) *APIError {
+	return &APIError{
+		StatusCode: statusCode,
+		Body:       data,
+	}
 }
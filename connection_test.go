@@ -0,0 +1,102 @@
+package gopensky_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Connection", func() {
+	Describe("NewConnection", func() {
+		It("connects to the given base url with the user agent", func() {
+			var userAgent, username, password string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/api/states/all"))
+
+				userAgent = r.UserAgent()
+				username, password, _ = r.BasicAuth()
+
+				w.Write([]byte(`{"time": 1, "states": []}`))
+			}))
+			defer server.Close()
+
+			conn, err := gopensky.NewConnection(context.Background(), "user", "pass",
+				gopensky.WithBaseURL(server.URL+"/api"),
+				gopensky.WithUserAgent("gopensky-test"),
+				gopensky.WithTimeout(5*time.Second),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			states, err := gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(states.Time).To(Equal(int64(1)))
+			Expect(userAgent).To(Equal("gopensky-test"))
+			Expect(username).To(Equal("user"))
+			Expect(password).To(Equal("pass"))
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gclient.Timeout).To(Equal(5 * time.Second))
+		})
+
+		It("uses the given http client and transport", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			httpClient := &http.Client{}
+
+			conn, err := gopensky.NewConnection(context.Background(), "", "",
+				gopensky.WithTimeout(time.Minute),
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithHTTPClient(httpClient),
+				gopensky.WithTransport(server.Client().Transport),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gclient).NotTo(BeIdenticalTo(httpClient))
+			Expect(gclient.Transport).To(BeIdenticalTo(server.Client().Transport))
+			Expect(gclient.Timeout).To(Equal(time.Minute))
+
+			// the caller client is left unchanged
+			Expect(httpClient.Transport).To(BeNil())
+			Expect(httpClient.Timeout).To(BeZero())
+
+			flights, err := gopensky.GetFlightsByInterval(conn, 1696755342, 1696758942)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flights).To(BeEmpty())
+
+			conn, err = gopensky.NewConnection(context.Background(), "", "", gopensky.WithHTTPClient(httpClient))
+			Expect(err).NotTo(HaveOccurred())
+
+			gclient, err = gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gclient).To(BeIdenticalTo(httpClient))
+		})
+
+		It("returns option errors", func() {
+			_, err := gopensky.NewConnection(context.Background(), "", "", gopensky.WithBaseURL("opensky"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid base url"))
+
+			_, err = gopensky.NewConnection(context.Background(), "", "", gopensky.WithBaseURL(":/invalid"))
+			Expect(err.Error()).To(ContainSubstring("invalid url"))
+
+			_, err = gopensky.NewConnection(context.Background(), "", "", gopensky.WithHTTPClient(nil))
+			Expect(err.Error()).To(ContainSubstring("nil http client"))
+
+			_, err = gopensky.NewConnection(context.Background(), "", "", gopensky.WithTimeout(-time.Second))
+			Expect(err.Error()).To(ContainSubstring("invalid timeout"))
+		})
+	})
+})
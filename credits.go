@@ -0,0 +1,286 @@
+package gopensky
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	rateLimitRemainingHeader = "X-Rate-Limit-Remaining"
+
+	// states request credit cost by the bounding box area (square degrees).
+	statesCostSmallArea  = 25
+	statesCostMediumArea = 100
+	statesCostLargeArea  = 400
+	statesMaxCost        = 4
+
+	// the states history is available up to one hour in the past.
+	statesHistory = time.Hour
+
+	// credit cost of the endpoints without area dependent cost.
+	defaultRequestCost = 1
+)
+
+// RateLimit is the API credit status reported by the last API response.
+type RateLimit struct {
+	// Remaining API credits (X-Rate-Limit-Remaining). It is -1 if not reported yet.
+	Remaining int
+
+	// Wait time before the credits are available again (X-Rate-Limit-Retry-After-Seconds).
+	RetryAfter time.Duration
+
+	// Time of the last API response that reported the rate limit.
+	UpdatedAt time.Time
+}
+
+// rateLimitTracker keeps the rate limit status of a connection.
+type rateLimitTracker struct {
+	mu     sync.Mutex
+	status RateLimit
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{status: RateLimit{Remaining: -1}}
+}
+
+func (t *rateLimitTracker) get() RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.status
+}
+
+// update parses the rate limit headers of an API response and returns the remaining credits (-1 if unknown).
+func (t *rateLimitTracker) update(header http.Header, now time.Time) int {
+	remaining, errRemaining := strconv.Atoi(header.Get(rateLimitRemainingHeader))
+	retrySeconds, errRetry := strconv.ParseInt(header.Get(rateLimitRetryAfterHeader), 10, 64)
+
+	if errRemaining != nil && errRetry != nil {
+		return -1
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.status.UpdatedAt = now
+	t.status.RetryAfter = 0
+
+	if errRemaining == nil {
+		t.status.Remaining = remaining
+	}
+
+	if errRetry == nil {
+		t.status.RetryAfter = time.Duration(retrySeconds) * time.Second
+	}
+
+	return t.status.Remaining
+}
+
+// GetRateLimit returns the API credit status reported by the last API response of the connection.
+func GetRateLimit(ctx context.Context) (RateLimit, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return RateLimit{}, err
+	}
+
+	return client.RateLimit(), nil
+}
+
+// EstimateStatesCost returns the API credits cost of a GetStates request for a given time.
+// The cost depends on the bounding box area in square degrees:
+// 1 credit up to 25, 2 up to 100, 3 up to 400 and 4 credits for larger areas or no bounding box.
+// A box crossing the antimeridian is sent as one request per side (see BoundingBoxOptions.Split),
+// its cost is the sum of the costs of both sides.
+// The historical states are priced as the most recent ones (time = 0), but they are only available
+// up to one hour in the past: older times return an ErrInvalidUnixTime error, as the API rejects them.
+func EstimateStatesCost(time int64, bBox *BoundingBoxOptions) (int, error) {
+	if time < 0 {
+		return 0, ErrInvalidUnixTime
+	}
+
+	if oldest := statesHistoryStart(); time > 0 && time < oldest {
+		return 0, fmt.Errorf("%w: %d is older than the states history (%d)", ErrInvalidUnixTime, time, oldest)
+	}
+
+	if bBox == nil {
+		return statesMaxCost, nil
+	}
+
+	if err := bBox.Validate(); err != nil {
+		return 0, err
+	}
+
+	return statesBoxCost(bBox), nil
+}
+
+// statesHistoryStart returns the oldest time of the states history in seconds since epoch (Unix time).
+func statesHistoryStart() int64 {
+	return time.Now().Add(-statesHistory).Unix()
+}
+
+// statesBoxCost returns the cost of the requests of a valid bounding box, one request per side of the antimeridian.
+func statesBoxCost(bBox *BoundingBoxOptions) int {
+	cost := 0
+	for _, region := range bBox.Split() {
+		cost += statesAreaCost(&region)
+	}
+
+	return cost
+}
+
+func statesAreaCost(bBox *BoundingBoxOptions) int {
+	area := math.Abs(bBox.Lamax-bBox.Lamin) * math.Abs(bBox.Lomax-bBox.Lomin)
+
+	switch {
+	case area <= statesCostSmallArea:
+		return 1
+	case area <= statesCostMediumArea:
+		return 2 //nolint:mnd
+	case area <= statesCostLargeArea:
+		return 3 //nolint:mnd
+	default:
+		return statesMaxCost
+	}
+}
+
+// requestCost returns the estimated API credits cost of a request.
+// The flights and tracks requests are counted as 1 credit whatever their time interval,
+// so the budget under-counts the long historical flights queries.
+func requestCost(endpoint string, queryParams url.Values) int {
+	switch endpoint {
+	case "/states/all":
+	case "/states/own":
+		// the own sensors states are not rate limited.
+		return 0
+	default:
+		return defaultRequestCost
+	}
+
+	coordinates := make([]float64, 0, 4) //nolint:mnd
+
+	for _, key := range []string{"lamin", "lomin", "lamax", "lomax"} {
+		value, err := strconv.ParseFloat(queryParams.Get(key), 64)
+		if err != nil {
+			return statesMaxCost
+		}
+
+		coordinates = append(coordinates, value)
+	}
+
+	bBox := NewBoundingBox(coordinates[0], coordinates[1], coordinates[2], coordinates[3])
+	if bBox.Validate() != nil {
+		return statesMaxCost
+	}
+
+	return statesBoxCost(bBox)
+}
+
+// WithCreditBudget sets a client side API credits budget (token bucket) of the connection.
+// The budget holds up to the given credits and is refilled at the same amount per period
+// (e.g. 4000 credits per 24 hours). It is also lowered to the remaining credits reported by the API.
+// When a request would exceed the budget, it waits for the refill if wait is true
+// or fails with ErrCreditBudgetExceeded otherwise.
+// The states requests are priced by bounding box area (see EstimateStatesCost), the flights and tracks
+// requests are counted as 1 credit each whatever their time interval.
+// Each attempt of a retried request (see WithRetryPolicy) is charged.
+func WithCreditBudget(credits int, period time.Duration, wait bool) Option {
+	return func(c *Connection) error {
+		if credits <= 0 || period <= 0 {
+			return fmt.Errorf("%w: %d credits per %s", errInvalidCreditBudget, credits, period)
+		}
+
+		c.budget = newCreditBudget(credits, period, wait, time.Now)
+
+		return nil
+	}
+}
+
+// creditBudget is a token bucket of API credits.
+type creditBudget struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // credits per second
+	last     time.Time
+	wait     bool
+	now      func() time.Time
+}
+
+func newCreditBudget(credits int, period time.Duration, wait bool, now func() time.Time) *creditBudget {
+	return &creditBudget{
+		capacity: float64(credits),
+		tokens:   float64(credits),
+		rate:     float64(credits) / period.Seconds(),
+		last:     now(),
+		wait:     wait,
+		now:      now,
+	}
+}
+
+func (b *creditBudget) refill() {
+	now := b.now()
+
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// take reserves the cost credits or returns the wait time until enough credits are available.
+func (b *creditBudget) take(cost int) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+
+	if b.tokens >= float64(cost) {
+		b.tokens -= float64(cost)
+
+		return 0, nil
+	}
+
+	if !b.wait || float64(cost) > b.capacity {
+		return 0, fmt.Errorf("%w: cost %d, available %.0f", ErrCreditBudgetExceeded, cost, math.Floor(b.tokens))
+	}
+
+	return time.Duration((float64(cost) - b.tokens) / b.rate * float64(time.Second)), nil
+}
+
+// sync lowers the budget to the remaining credits reported by the API.
+func (b *creditBudget) sync(remaining int) {
+	if remaining < 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens = min(b.tokens, float64(remaining))
+}
+
+// reserveCredits takes the request cost from the connection credit budget, waiting for the refill if configured.
+func (c *Connection) reserveCredits(ctx context.Context, cost int) error {
+	if c.budget == nil || cost <= 0 {
+		return nil
+	}
+
+	for {
+		wait, err := c.budget.take(cost)
+		if err != nil || wait == 0 {
+			return err
+		}
+
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "waiting for api credit budget",
+			slog.Int("cost", cost), slog.Duration("wait", wait))
+
+		if err := c.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
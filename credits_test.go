@@ -0,0 +1,210 @@
+package gopensky_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Credits", func() {
+	var (
+		requests  atomic.Int32
+		remaining atomic.Int32
+		server    *httptest.Server
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		remaining.Store(100)
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+
+			w.Header().Set("X-Rate-Limit-Remaining", fmt.Sprintf("%d", remaining.Load()))
+			w.Write([]byte(`{"time": 1, "states": []}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("GetRateLimit", func() {
+		It("returns the rate limit reported by the last response", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "", gopensky.WithBaseURL(server.URL))
+			Expect(err).NotTo(HaveOccurred())
+
+			rateLimit, err := gopensky.GetRateLimit(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rateLimit.Remaining).To(Equal(-1))
+			Expect(rateLimit.UpdatedAt.IsZero()).To(BeTrue())
+
+			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			rateLimit, err = gopensky.GetRateLimit(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rateLimit.Remaining).To(Equal(100))
+			Expect(rateLimit.UpdatedAt.IsZero()).To(BeFalse())
+
+			_, err = gopensky.GetRateLimit(context.Background())
+			Expect(err.Error()).To(ContainSubstring("invalid context key"))
+		})
+	})
+
+	Describe("EstimateStatesCost", func() {
+		It("estimates the credit cost by bounding box area", func() {
+			tests := []struct {
+				bBox  *gopensky.BoundingBoxOptions
+				wants int
+			}{
+				{bBox: nil, wants: 4},
+				{bBox: gopensky.NewBoundingBox(45, 5, 50, 10), wants: 1},
+				{bBox: gopensky.NewBoundingBox(40, 0, 50, 10), wants: 2},
+				{bBox: gopensky.NewBoundingBox(30, 0, 50, 20), wants: 3},
+				{bBox: gopensky.NewBoundingBox(30, 0, 50, 20.5), wants: 4},
+				// antimeridian boxes are priced per side, as sent by GetStates.
+				{bBox: gopensky.NewBoundingBox(50, 179, 51, -179), wants: 2},
+				{bBox: gopensky.NewBoundingBox(30, 170, 50, -170), wants: 6},
+			}
+
+			for _, test := range tests {
+				cost, err := gopensky.EstimateStatesCost(0, test.bBox)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cost).To(Equal(test.wants))
+
+				params := gopensky.GetStateRequestParams(0, nil, test.bBox, false)
+				Expect(gopensky.RequestCost("/states/all", params)).To(Equal(test.wants))
+			}
+
+			for _, region := range gopensky.NewBoundingBox(50, 179, 51, -179).Split() {
+				cost, err := gopensky.EstimateStatesCost(0, &region)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cost).To(Equal(1))
+			}
+
+			_, err := gopensky.EstimateStatesCost(0, gopensky.NewBoundingBox(0, 0, 95, 10))
+			Expect(err).To(MatchError(gopensky.ErrInvalidBoundingBox))
+
+			cost, err := gopensky.EstimateStatesCost(time.Now().Add(-time.Minute).Unix(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cost).To(Equal(4))
+
+			_, err = gopensky.EstimateStatesCost(time.Now().Add(-2*time.Hour).Unix(), nil)
+			Expect(err).To(MatchError(gopensky.ErrInvalidUnixTime))
+
+			_, err = gopensky.EstimateStatesCost(-1, nil)
+			Expect(err).To(Equal(gopensky.ErrInvalidUnixTime))
+
+			Expect(gopensky.RequestCost("/flights/all", url.Values{})).To(Equal(1))
+		})
+	})
+
+	Describe("WithCreditBudget", func() {
+		It("fails fast when the budget would go negative", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithCreditBudget(6, 24*time.Hour, false),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(errors.Is(err, gopensky.ErrCreditBudgetExceeded)).To(BeTrue())
+			Expect(requests.Load()).To(Equal(int32(1)))
+
+			_, err = gopensky.GetStates(conn, 0, nil, gopensky.NewBoundingBox(45, 5, 50, 10), false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests.Load()).To(Equal(int32(2)))
+		})
+
+		It("follows the remaining credits reported by the api", func() {
+			remaining.Store(2)
+
+			conn, err := gopensky.NewConnection(context.Background(), "", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithCreditBudget(4000, 24*time.Hour, false),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(errors.Is(err, gopensky.ErrCreditBudgetExceeded)).To(BeTrue())
+		})
+
+		It("waits for the budget refill", func() {
+			now := time.Unix(1696755342, 0)
+
+			conn, err := gopensky.NewConnection(context.Background(), "", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithCreditBudget(4, time.Hour, true),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			var waits []time.Duration
+
+			gopensky.SetCreditBudgetNow(conn, func() time.Time { return now })
+			gopensky.SetConnectionSleep(conn, func(_ context.Context, wait time.Duration) error {
+				waits = append(waits, wait)
+				now = now.Add(wait)
+
+				return nil
+			})
+
+			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStates(conn, 0, nil, gopensky.NewBoundingBox(40, 0, 50, 10), false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waits).To(Equal([]time.Duration{30 * time.Minute}))
+			Expect(requests.Load()).To(Equal(int32(2)))
+		})
+
+		It("charges each retried attempt", func() {
+			failures := atomic.Int32{}
+			failures.Store(2)
+
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+
+				if failures.Add(-1) >= 0 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				w.Write([]byte(`{"time": 1, "states": []}`))
+			})
+
+			conn, err := gopensky.NewConnection(context.Background(), "", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithCreditBudget(2, 24*time.Hour, false),
+				gopensky.WithRetryPolicy(gopensky.RetryPolicy{MaxAttempts: 3, RetryableStatusCodes: []int{503}}),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStates(conn, 0, nil, gopensky.NewBoundingBox(45, 5, 50, 10), false)
+			Expect(errors.Is(err, gopensky.ErrCreditBudgetExceeded)).To(BeTrue())
+			Expect(requests.Load()).To(Equal(int32(2)))
+		})
+
+		It("returns invalid budget error", func() {
+			_, err := gopensky.NewConnection(context.Background(), "", "", gopensky.WithCreditBudget(0, time.Hour, true))
+			Expect(err.Error()).To(ContainSubstring("invalid credit budget"))
+		})
+	})
+})
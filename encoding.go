@@ -0,0 +1,102 @@
+package gopensky
+
+// Response returns the states in the OpenSky API positional format,
+// json.Marshal of the response produces the /states/all wire form.
+// The rows have the category field only if the states are extended.
+func (s *States) Response() StatesResponse {
+	response := StatesResponse{
+		Time: s.Time,
+	}
+
+	if s.States == nil {
+		return response
+	}
+
+	response.States = make([][]any, 0, len(s.States))
+
+	for index := range s.States {
+		row := s.States[index].Row()
+		if !s.Extended {
+			row = row[:stateVecCategoryIndex]
+		}
+
+		response.States = append(response.States, row)
+	}
+
+	return response
+}
+
+// Row returns the state vector as an OpenSky API positional row, including the category field.
+func (s *StateVector) Row() []any {
+	row := make([]any, stateVecCategoryIndex+1)
+
+	row[stateVecIaco24Index] = s.Icao24
+	row[stateVecCallsignIndex] = optionalValue(s.Callsign)
+	row[stateVecCountryIndex] = s.OriginCountry
+	row[stateVecTimePositionIndex] = optionalValue(s.TimePosition)
+	row[stateVecLastContactIndex] = s.LastContact
+	row[stateVecLongitudeIndex] = optionalValue(s.Longitude)
+	row[stateVecLatitudeIndex] = optionalValue(s.Latitude)
+	row[stateVecBaroAltitudeIndex] = optionalValue(s.BaroAltitude)
+	row[stateVecOnGroundIndex] = s.OnGround
+	row[stateVecVelocityIndex] = optionalValue(s.Velocity)
+	row[stateVecTrueTrackIndex] = optionalValue(s.TrueTrack)
+	row[stateVecVerticalRateIndex] = optionalValue(s.VerticalRate)
+	row[stateVecGeoAltitudeIndex] = optionalValue(s.GeoAltitude)
+	row[stateVecSquawkIndex] = optionalValue(s.Squawk)
+	row[stateVecSpiIndex] = s.Spi
+	row[stateVecPositionSourceIndex] = int(s.PositionSource)
+	row[stateVecCategoryIndex] = int(s.Category)
+
+	if s.Sensors != nil {
+		row[stateVecSensorsIndex] = s.Sensors
+	}
+
+	return row
+}
+
+// Response returns the flight track in the OpenSky API positional format,
+// json.Marshal of the response produces the /tracks/all wire form.
+func (t *FlightTrack) Response() FlightTrackResponse {
+	response := FlightTrackResponse{
+		Icao24:    t.Icao24,
+		StartTime: float64(t.StartTime),
+		EndTime:   float64(t.EndTime),
+		Callsign:  t.Callsign,
+	}
+
+	if t.Path == nil {
+		return response
+	}
+
+	response.Path = make([][]any, 0, len(t.Path))
+
+	for index := range t.Path {
+		response.Path = append(response.Path, t.Path[index].Row())
+	}
+
+	return response
+}
+
+// Row returns the waypoint as an OpenSky API positional row.
+func (w *WayPoint) Row() []any {
+	row := make([]any, trackOnGroundIndex+1)
+
+	row[trackTimeIndex] = w.Time
+	row[trackLatitudeIndex] = optionalValue(w.Latitude)
+	row[trackLongitudeIndex] = optionalValue(w.Longitude)
+	row[trackBaroAltitudeIndex] = optionalValue(w.BaroAltitude)
+	row[trackTureTrackIndex] = optionalValue(w.TrueTrack)
+	row[trackOnGroundIndex] = w.OnGround
+
+	return row
+}
+
+// optionalValue returns the pointed value or an untyped nil (JSON null) if the pointer is nil.
+func optionalValue[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
@@ -0,0 +1,122 @@
+package gopensky_test
+
+import (
+	"encoding/json"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Encoding", func() {
+	Describe("States", func() {
+		It("round trips the states through the positional format", func() {
+			data, err := os.ReadFile("mock_data/all_states.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			states, err := gopensky.DecodeStatesResponse(data)
+			Expect(err).NotTo(HaveOccurred())
+
+			states.States[0].Sensors = []int{12, 34}
+
+			encoded, err := json.Marshal(states.Response())
+			Expect(err).NotTo(HaveOccurred())
+
+			decoded, err := gopensky.DecodeStatesResponse(encoded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(states))
+
+			var sourceRep, statesRep gopensky.StatesResponse
+
+			Expect(json.Unmarshal(data, &sourceRep)).To(Succeed())
+			Expect(json.Unmarshal(encoded, &statesRep)).To(Succeed())
+			Expect(statesRep.States[0]).To(HaveLen(len(sourceRep.States[0])))
+
+			for index, row := range statesRep.States {
+				stvec, err := gopensky.DecodeRawStateVector(row)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*stvec).To(Equal(states.States[index]))
+			}
+		})
+
+		It("encodes the positional rows", func() {
+			callsign := "SWR100  "
+			longitude := 8.5
+
+			states := gopensky.States{
+				Time: 1696755342,
+				States: []gopensky.StateVector{{
+					Icao24:         "4b1806",
+					Callsign:       &callsign,
+					OriginCountry:  "Switzerland",
+					LastContact:    1696755341,
+					Longitude:      &longitude,
+					PositionSource: gopensky.PositionSourceMLAT,
+				}},
+			}
+
+			encoded, err := json.Marshal(states.Response())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(encoded)).To(Equal(`{"time":1696755342,"states":[["4b1806","SWR100  ","Switzerland",` +
+				`null,1696755341,8.5,null,null,false,null,null,null,null,null,null,false,2]]}`))
+
+			// the category field is only encoded for the extended states.
+			states.Extended = true
+			states.States[0].Category = gopensky.CategoryHeavy
+
+			encoded, err = json.Marshal(states.Response())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(encoded)).To(HaveSuffix(`null,null,false,2,6]]}`))
+
+			decoded, err := gopensky.DecodeStatesResponse(encoded)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(&states))
+
+			encoded, err = json.Marshal((&gopensky.States{Time: 1}).Response())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(encoded)).To(Equal(`{"time":1,"states":null}`))
+		})
+	})
+
+	Describe("FlightTrack", func() {
+		It("round trips the track through the positional format", func() {
+			callsign := "POE2136"
+			latitude := 40.6895
+			longitude := -74.1745
+			altitude := 1981.2
+			trueTrack := 34.0
+
+			track := gopensky.FlightTrack{
+				Icao24:    "c060b9",
+				StartTime: 1689193028,
+				EndTime:   1689197805,
+				Callsign:  &callsign,
+				Path: []gopensky.WayPoint{
+					{Time: 1689193028, OnGround: true},
+					{
+						Time:         1689193100,
+						Latitude:     &latitude,
+						Longitude:    &longitude,
+						BaroAltitude: &altitude,
+						TrueTrack:    &trueTrack,
+					},
+				},
+			}
+
+			encoded, err := json.Marshal(track.Response())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(encoded)).To(ContainSubstring(
+				`"path":[[1689193028,null,null,null,null,true],[1689193100,40.6895,-74.1745,1981.2,34,false]]`))
+
+			var response gopensky.FlightTrackResponse
+
+			Expect(json.Unmarshal(encoded, &response)).To(Succeed())
+
+			decoded, err := gopensky.ParseFlightTrackResponse(&response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(track))
+		})
+	})
+})
@@ -0,0 +1,197 @@
+package gopensky
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PositionSource is the origin of a state vector position.
+type PositionSource int
+
+const (
+	PositionSourceADSB    PositionSource = iota // ADS-B
+	PositionSourceASTERIX                       // ASTERIX
+	PositionSourceMLAT                          // Multilateration
+	PositionSourceFLARM                         // FLARM
+)
+
+var positionSourceNames = [...]string{ //nolint:gochecknoglobals
+	PositionSourceADSB:    "ads-b",
+	PositionSourceASTERIX: "asterix",
+	PositionSourceMLAT:    "mlat",
+	PositionSourceFLARM:   "flarm",
+}
+
+// String returns the position source name (e.g. ads-b, mlat).
+func (p PositionSource) String() string {
+	if p >= 0 && int(p) < len(positionSourceNames) {
+		return positionSourceNames[p]
+	}
+
+	return "PositionSource(" + strconv.Itoa(int(p)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, the position source is encoded as its name.
+func (p PositionSource) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, it accepts the case-insensitive position source name or number,
+// or the PositionSource(N) form of the unknown values.
+func (p *PositionSource) UnmarshalText(text []byte) error {
+	value, err := parseEnum(string(text), "PositionSource", positionSourceNames[:])
+	if err != nil {
+		return fmt.Errorf("%w: %q", errInvalidPositionSource, text)
+	}
+
+	*p = PositionSource(value)
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts the position source name or number, null is a no-op.
+func (p *PositionSource) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, p)
+}
+
+// AircraftCategory is the aircraft (ADS-B emitter) category of a state vector.
+type AircraftCategory int
+
+const (
+	CategoryNoInfo           AircraftCategory = iota // No information at all
+	CategoryNoADSBInfo                               // No ADS-B Emitter Category Information
+	CategoryLight                                    // Light (< 15500 lbs)
+	CategorySmall                                    // Small (15500 to 75000 lbs)
+	CategoryLarge                                    // Large (75000 to 300000 lbs)
+	CategoryHighVortexLarge                          // High Vortex Large (aircraft such as B-757)
+	CategoryHeavy                                    // Heavy (> 300000 lbs)
+	CategoryHighPerformance                          // High Performance (> 5g acceleration and 400 kts)
+	CategoryRotorcraft                               // Rotorcraft
+	CategoryGlider                                   // Glider / sailplane
+	CategoryLighterThanAir                           // Lighter-than-air
+	CategoryParachutist                              // Parachutist / Skydiver
+	CategoryUltralight                               // Ultralight / hang-glider / paraglider
+	CategoryReserved                                 // Reserved
+	CategoryUAV                                      // Unmanned Aerial Vehicle
+	CategorySpace                                    // Space / Trans-atmospheric vehicle
+	CategorySurfaceEmergency                         // Surface Vehicle – Emergency Vehicle
+	CategorySurfaceService                           // Surface Vehicle – Service Vehicle
+	CategoryPointObstacle                            // Point Obstacle (includes tethered balloons)
+	CategoryClusterObstacle                          // Cluster Obstacle
+	CategoryLineObstacle                             // Line Obstacle
+)
+
+var aircraftCategoryNames = [...]string{ //nolint:gochecknoglobals
+	CategoryNoInfo:           "no-info",
+	CategoryNoADSBInfo:       "no-adsb-info",
+	CategoryLight:            "light",
+	CategorySmall:            "small",
+	CategoryLarge:            "large",
+	CategoryHighVortexLarge:  "high-vortex-large",
+	CategoryHeavy:            "heavy",
+	CategoryHighPerformance:  "high-performance",
+	CategoryRotorcraft:       "rotorcraft",
+	CategoryGlider:           "glider",
+	CategoryLighterThanAir:   "lighter-than-air",
+	CategoryParachutist:      "parachutist",
+	CategoryUltralight:       "ultralight",
+	CategoryReserved:         "reserved",
+	CategoryUAV:              "uav",
+	CategorySpace:            "space",
+	CategorySurfaceEmergency: "surface-emergency",
+	CategorySurfaceService:   "surface-service",
+	CategoryPointObstacle:    "point-obstacle",
+	CategoryClusterObstacle:  "cluster-obstacle",
+	CategoryLineObstacle:     "line-obstacle",
+}
+
+// String returns the aircraft category name (e.g. heavy, rotorcraft).
+func (c AircraftCategory) String() string {
+	if c >= 0 && int(c) < len(aircraftCategoryNames) {
+		return aircraftCategoryNames[c]
+	}
+
+	return "AircraftCategory(" + strconv.Itoa(int(c)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, the category is encoded as its name.
+func (c AircraftCategory) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, it accepts the case-insensitive category name or number,
+// or the AircraftCategory(N) form of the unknown values.
+func (c *AircraftCategory) UnmarshalText(text []byte) error {
+	value, err := parseEnum(string(text), "AircraftCategory", aircraftCategoryNames[:])
+	if err != nil {
+		return fmt.Errorf("%w: %q", errInvalidAircraftCategory, text)
+	}
+
+	*c = AircraftCategory(value)
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts the category name or number, null is a no-op.
+func (c *AircraftCategory) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, c)
+}
+
+// IsSurfaceVehicle returns true for the emergency and service surface vehicles.
+func (c AircraftCategory) IsSurfaceVehicle() bool {
+	return c == CategorySurfaceEmergency || c == CategorySurfaceService
+}
+
+// IsRotorcraft returns true for the rotorcraft category.
+func (c AircraftCategory) IsRotorcraft() bool {
+	return c == CategoryRotorcraft
+}
+
+// IsObstacle returns true for the point, cluster and line obstacles (e.g. tethered balloons).
+func (c AircraftCategory) IsObstacle() bool {
+	return c == CategoryPointObstacle || c == CategoryClusterObstacle || c == CategoryLineObstacle
+}
+
+// unmarshalEnumJSON unmarshals a JSON string or number into the enum text unmarshaler.
+// The JSON null leaves the enum unchanged, as expected from the json.Unmarshaler implementations.
+func unmarshalEnumJSON(data []byte, enum encoding.TextUnmarshaler) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var text string
+
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err //nolint:wrapcheck
+		}
+	} else {
+		text = string(data)
+	}
+
+	return enum.UnmarshalText([]byte(text))
+}
+
+// parseEnum returns the index of the case-insensitive name or the parsed number,
+// the number of the unknown values may be in the typeName(N) form returned by the String methods.
+func parseEnum(text string, typeName string, names []string) (int, error) {
+	for index, name := range names {
+		if strings.EqualFold(text, name) {
+			return index, nil
+		}
+	}
+
+	if number, found := strings.CutPrefix(text, typeName+"("); found {
+		text = strings.TrimSuffix(number, ")")
+	}
+
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return value, nil
+}
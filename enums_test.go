@@ -0,0 +1,113 @@
+package gopensky_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Enums", func() {
+	Describe("PositionSource", func() {
+		It("returns the position source names", func() {
+			Expect(gopensky.PositionSourceADSB.String()).To(Equal("ads-b"))
+			Expect(gopensky.PositionSourceMLAT.String()).To(Equal("mlat"))
+			Expect(gopensky.PositionSource(7).String()).To(Equal("PositionSource(7)"))
+		})
+
+		It("marshals the position source as name", func() {
+			data, err := json.Marshal(gopensky.PositionSourceFLARM)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`"flarm"`))
+
+			var source gopensky.PositionSource
+
+			Expect(json.Unmarshal([]byte(`"asterix"`), &source)).To(Succeed())
+			Expect(source).To(Equal(gopensky.PositionSourceASTERIX))
+
+			Expect(json.Unmarshal([]byte(`2`), &source)).To(Succeed())
+			Expect(source).To(Equal(gopensky.PositionSourceMLAT))
+
+			Expect(json.Unmarshal([]byte(`"ADS-B"`), &source)).To(Succeed())
+			Expect(source).To(Equal(gopensky.PositionSourceADSB))
+
+			Expect(json.Unmarshal([]byte(`"radar"`), &source)).To(MatchError(ContainSubstring("invalid position source")))
+			Expect(json.Unmarshal([]byte(`"PositionSource(x)"`), &source)).To(HaveOccurred())
+		})
+
+		It("round-trips the unknown position sources", func() {
+			data, err := json.Marshal(gopensky.PositionSource(7))
+			Expect(err).NotTo(HaveOccurred())
+
+			var source gopensky.PositionSource
+
+			Expect(json.Unmarshal(data, &source)).To(Succeed())
+			Expect(source).To(Equal(gopensky.PositionSource(7)))
+		})
+
+		It("ignores the null position source", func() {
+			stvec := gopensky.StateVector{PositionSource: gopensky.PositionSourceMLAT}
+
+			Expect(json.Unmarshal([]byte(`{"positionSource":null,"category":null}`), &stvec)).To(Succeed())
+			Expect(stvec.PositionSource).To(Equal(gopensky.PositionSourceMLAT))
+			Expect(stvec.Category).To(Equal(gopensky.CategoryNoInfo))
+		})
+	})
+
+	Describe("AircraftCategory", func() {
+		It("returns the category names", func() {
+			Expect(gopensky.CategoryHeavy.String()).To(Equal("heavy"))
+			Expect(gopensky.CategoryUAV.String()).To(Equal("uav"))
+			Expect(gopensky.AircraftCategory(21).String()).To(Equal("AircraftCategory(21)"))
+		})
+
+		It("marshals the category as name", func() {
+			data, err := json.Marshal(gopensky.StateVector{Category: gopensky.CategoryHighVortexLarge})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"positionSource":"ads-b","category":"high-vortex-large"`))
+
+			var stvec gopensky.StateVector
+
+			Expect(json.Unmarshal(data, &stvec)).To(Succeed())
+			Expect(stvec.Category).To(Equal(gopensky.CategoryHighVortexLarge))
+
+			var category gopensky.AircraftCategory
+
+			Expect(json.Unmarshal([]byte(`"14"`), &category)).To(Succeed())
+			Expect(category).To(Equal(gopensky.CategoryUAV))
+
+			Expect(json.Unmarshal([]byte(`null`), &category)).To(Succeed())
+			Expect(category).To(Equal(gopensky.CategoryUAV))
+		})
+
+		It("round-trips the unknown categories", func() {
+			data, err := json.Marshal(gopensky.AircraftCategory(21))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`"AircraftCategory(21)"`))
+
+			var category gopensky.AircraftCategory
+
+			Expect(json.Unmarshal(data, &category)).To(Succeed())
+			Expect(category).To(Equal(gopensky.AircraftCategory(21)))
+
+			Expect(category.UnmarshalText([]byte("AircraftCategory(-1)"))).To(Succeed())
+			Expect(category).To(Equal(gopensky.AircraftCategory(-1)))
+		})
+
+		It("classifies the categories", func() {
+			Expect(gopensky.CategorySurfaceEmergency.IsSurfaceVehicle()).To(BeTrue())
+			Expect(gopensky.CategorySurfaceService.IsSurfaceVehicle()).To(BeTrue())
+			Expect(gopensky.CategoryHeavy.IsSurfaceVehicle()).To(BeFalse())
+
+			Expect(gopensky.CategoryRotorcraft.IsRotorcraft()).To(BeTrue())
+			Expect(gopensky.CategoryGlider.IsRotorcraft()).To(BeFalse())
+
+			Expect(gopensky.CategoryPointObstacle.IsObstacle()).To(BeTrue())
+			Expect(gopensky.CategoryClusterObstacle.IsObstacle()).To(BeTrue())
+			Expect(gopensky.CategoryLineObstacle.IsObstacle()).To(BeTrue())
+			Expect(gopensky.CategoryLighterThanAir.IsObstacle()).To(BeFalse())
+		})
+	})
+})
@@ -2,11 +2,41 @@ package gopensky
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 )
 
 var (
 	errContextKey = errors.New("invalid context key")
 
+	errInvalidBaseURL = errors.New("invalid base url")
+	errNilHTTPClient  = errors.New("nil http client")
+	errInvalidTimeout = errors.New("invalid timeout")
+
+	errTokenRequest     = errors.New("oauth2 token request failed")
+	errEmptyAccessToken = errors.New("empty access token")
+
+	errInvalidCreditBudget = errors.New("invalid credit budget")
+
+	errInvalidRangeConcurrency = errors.New("invalid range concurrency")
+
+	errInvalidWatchDuration = errors.New("invalid watcher duration")
+
+	errInvalidStoreLimit = errors.New("invalid state store limit")
+
+	errNilResponse = errors.New("middleware returned nil response")
+
+	errUnexpectedJSONToken = errors.New("unexpected json token")
+
+	errBBoxLatitudeRange  = errors.New("latitude out of range [-90, 90]")
+	errBBoxLongitudeRange = errors.New("longitude out of range [-180, 180]")
+	errBBoxLatitudeOrder  = errors.New("lower latitude bound greater than upper bound")
+
+	errInvalidPositionSource   = errors.New("invalid position source")
+	errInvalidAircraftCategory = errors.New("invalid aircraft category")
+
 	errStateVecDataCount      = errors.New("invalid state vector data count")
 	errStateVecIcao24         = errors.New("state vector icao24 assertion failed")
 	errStateVecCallsign       = errors.New("state vector callsign assertion failed")
@@ -38,6 +68,26 @@ var (
 	ErrInvalidAirportName  = errors.New("invalid airport name")
 	ErrInvalidAircraftName = errors.New("invalid aircraft name")
 	ErrInvalidUnixTime     = errors.New("invalid unix time")
+	ErrInvalidSquawk       = errors.New("invalid squawk code")
+	ErrInvalidRadius       = errors.New("invalid radius")
+
+	// ErrInvalidBoundingBox is matched by the bounding box validation errors (see BoundingBoxError).
+	ErrInvalidBoundingBox = errors.New("invalid bounding box")
+
+	// ErrRateLimited is matched by API errors with 429 Too Many Requests status code.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrUnauthorized is matched by API errors with 401 Unauthorized or 403 Forbidden status codes.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrNotFound is matched by API errors with 404 Not Found status code.
+	ErrNotFound = errors.New("not found")
+
+	// ErrServerUnavailable is matched by API errors with 5xx status codes.
+	ErrServerUnavailable = errors.New("server unavailable")
+
+	// ErrCreditBudgetExceeded is returned when a request would exceed the connection credit budget.
+	ErrCreditBudgetExceeded = errors.New("api credit budget exceeded")
 )
 
 type connectionError struct {
@@ -52,10 +102,113 @@ func (c connectionError) Unwrap() error {
 	return c.err
 }
 
-type httpModelError struct {
-	Message string `json:"message"`
+// StateVectorError is returned when a state vector row of a states response can't be decoded.
+// Use errors.As to get the row and field index.
+type StateVectorError struct {
+	// Index of the state vector row in the response.
+	Row int
+
+	// Positional index of the invalid field in the row.
+	Index int
+
+	// Name of the invalid field, it's empty if the row itself is invalid (e.g. missing fields).
+	Field string
+
+	// Decode error.
+	Err error
+}
+
+func (e *StateVectorError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+	}
+
+	return fmt.Sprintf("row %d field %d (%s): %v", e.Row, e.Index, e.Field, e.Err)
+}
+
+func (e *StateVectorError) Unwrap() error {
+	return e.Err
 }
 
-func (e httpModelError) Error() string {
-	return e.Message
+// BoundingBoxError is returned when a bounding box has invalid coordinates.
+// It matches ErrInvalidBoundingBox with errors.Is.
+type BoundingBoxError struct {
+	// Name of the invalid coordinate (lamin, lomin, lamax or lomax).
+	Field string
+
+	// Invalid coordinate value in decimal degrees.
+	Value float64
+
+	// Validation error.
+	Err error
+}
+
+func (e *BoundingBoxError) Error() string {
+	return fmt.Sprintf("%s %s %g: %v", ErrInvalidBoundingBox, e.Field, e.Value, e.Err)
+}
+
+func (e *BoundingBoxError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrInvalidBoundingBox.
+func (e *BoundingBoxError) Is(target error) bool {
+	return target == ErrInvalidBoundingBox //nolint:errorlint
+}
+
+// APIError is returned when the API responds with a non-successful status code.
+// Use errors.Is with ErrRateLimited, ErrUnauthorized, ErrNotFound or ErrServerUnavailable
+// to check the error kind, and errors.As to inspect the response.
+type APIError struct {
+	// HTTP response status code.
+	StatusCode int
+
+	// API endpoint of the request (e.g. /states/all).
+	Endpoint string
+
+	// Query parameters of the request.
+	Query url.Values
+
+	// Response body.
+	Body []byte
+
+	// Rate limit (X-Rate-Limit-*), Retry-After and WWW-Authenticate response headers.
+	Header http.Header
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s", http.StatusText(e.StatusCode), e.Body)
+}
+
+// Is reports whether the error status code matches the given sentinel error.
+func (e *APIError) Is(target error) bool {
+	switch target { //nolint:errorlint
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrServerUnavailable:
+		return e.StatusCode/100 == 5 //nolint:mnd
+	}
+
+	return false
+}
+
+// apiErrorHeader returns the response headers which are relevant to the API errors.
+func apiErrorHeader(header http.Header) http.Header {
+	errHeader := make(http.Header)
+
+	for key, values := range header {
+		canonicalKey := http.CanonicalHeaderKey(key)
+
+		if strings.HasPrefix(canonicalKey, "X-Rate-Limit-") ||
+			canonicalKey == retryAfterHeader ||
+			canonicalKey == "Www-Authenticate" {
+			errHeader[canonicalKey] = values
+		}
+	}
+
+	return errHeader
 }
@@ -1,8 +1,11 @@
 package gopensky_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 
 	"github.com/navidys/gopensky"
 	. "github.com/onsi/ginkgo/v2"
@@ -17,10 +20,65 @@ var _ = Describe("Errors", func() {
 		})
 	})
 
-	Describe("httpModelError ", func() {
+	Describe("APIError ", func() {
 		It("Error()", func() {
 			httpError := gopensky.HandleError(http.StatusNotFound, []byte("test data"))
 			Expect(httpError.Error()).To(Equal(http.StatusText(http.StatusNotFound) + " test data"))
 		})
+
+		It("matches the sentinel errors", func() {
+			tests := []struct {
+				statusCode int
+				wants      error
+			}{
+				{statusCode: http.StatusTooManyRequests, wants: gopensky.ErrRateLimited},
+				{statusCode: http.StatusUnauthorized, wants: gopensky.ErrUnauthorized},
+				{statusCode: http.StatusForbidden, wants: gopensky.ErrUnauthorized},
+				{statusCode: http.StatusNotFound, wants: gopensky.ErrNotFound},
+				{statusCode: http.StatusInternalServerError, wants: gopensky.ErrServerUnavailable},
+				{statusCode: http.StatusServiceUnavailable, wants: gopensky.ErrServerUnavailable},
+				{statusCode: http.StatusBadRequest, wants: nil},
+			}
+
+			sentinels := []error{
+				gopensky.ErrRateLimited,
+				gopensky.ErrUnauthorized,
+				gopensky.ErrNotFound,
+				gopensky.ErrServerUnavailable,
+			}
+
+			for _, test := range tests {
+				err := fmt.Errorf("wrapped: %w", gopensky.HandleError(test.statusCode, nil))
+
+				for _, sentinel := range sentinels {
+					Expect(errors.Is(err, sentinel)).To(Equal(sentinel == test.wants))
+				}
+			}
+		})
+
+		It("carries the request and response details", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-Rate-Limit-Retry-After-Seconds", "60")
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte("Too many requests"))
+			}))
+			defer server.Close()
+
+			conn, err := gopensky.NewConnection(context.Background(), "", "", gopensky.WithBaseURL(server.URL))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStates(conn, 0, []string{"c060b9"}, nil, false)
+			Expect(err).To(MatchError(gopensky.ErrRateLimited))
+
+			var apiErr *gopensky.APIError
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.StatusCode).To(Equal(http.StatusTooManyRequests))
+			Expect(apiErr.Endpoint).To(Equal("/states/all"))
+			Expect(apiErr.Query.Get("icao24")).To(Equal("c060b9"))
+			Expect(string(apiErr.Body)).To(Equal("Too many requests"))
+			Expect(apiErr.Header.Get("X-Rate-Limit-Retry-After-Seconds")).To(Equal("60"))
+			Expect(apiErr.Header.Get("Content-Type")).To(BeEmpty())
+		})
 	})
 })
@@ -2,8 +2,11 @@ package gopensky
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 var (
@@ -26,3 +29,55 @@ func GetClient(ctx context.Context) (*http.Client, error) {
 
 	return nil, fmt.Errorf("%w %s", errContextKey, clientKey)
 }
+
+func SetClientCredentialsNow(auth *ClientCredentialsAuth, now func() time.Time) {
+	auth.now = now
+}
+
+var RetryAfter = retryAfter
+
+func SetConnectionSleep(ctx context.Context, sleep func(ctx context.Context, wait time.Duration) error) {
+	if c, ok := ctx.Value(clientKey).(*Connection); ok {
+		c.sleep = sleep
+	}
+}
+
+func RetryPolicyBackoff(policy RetryPolicy, retry int) time.Duration {
+	return policy.backoff(retry)
+}
+
+var RequestCost = requestCost
+
+func SetCreditBudgetNow(ctx context.Context, now func() time.Time) {
+	if c, ok := ctx.Value(clientKey).(*Connection); ok && c.budget != nil {
+		c.budget.now = now
+		c.budget.last = now()
+	}
+}
+
+func SetMemoryCacheNow(cache *MemoryCache, now func() time.Time) {
+	cache.now = now
+}
+
+func SetFileCacheNow(cache *FileCache, now func() time.Time) {
+	cache.now = now
+}
+
+func CachePolicyTTL(policy CachePolicy, queryParams url.Values, now time.Time) time.Duration {
+	return policy.ttl(queryParams, now)
+}
+
+var (
+	SplitTimeRange = splitTimeRange
+	MergeFlights   = mergeFlights
+)
+
+func DecodeStatesResponse(data []byte) (*States, error) {
+	var statesRep statesResponse
+
+	if err := json.Unmarshal(data, &statesRep); err != nil {
+		return nil, err
+	}
+
+	return statesRep.states(), nil
+}
@@ -58,52 +58,42 @@ type FlighData struct {
 // GetArrivalsByAirport retrieves flights for a certain airport which arrived within a given time interval [being, end].
 // The given time interval must not be larger than seven days!
 func GetArrivalsByAirport(ctx context.Context, airport string, begin int64, end int64) ([]FlighData, error) {
-	var flighDataList []FlighData
-
-	conn, err := getClient(ctx)
+	client, err := contextClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("client: %w", err)
-	}
-
-	if airport == "" {
-		return nil, ErrInvalidAirportName
-	}
-
-	if begin <= 0 || end <= 0 {
-		return nil, ErrInvalidUnixTime
+		return nil, err
 	}
 
-	requestParams := getFlightsRequestParams(airport, "", begin, end)
-
-	response, err := conn.doGetRequest(ctx, "/flights/arrival", requestParams)
-	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
+	return client.GetArrivalsByAirport(ctx, airport, begin, end)
+}
 
-	errRespProcess := response.process(&flighDataList)
+// GetArrivalsByAirport retrieves flights for a certain airport which arrived within a given time interval [being, end].
+// The given time interval must not be larger than seven days!
+func (c *Client) GetArrivalsByAirport(ctx context.Context, airport string, begin int64, end int64,
+) ([]FlighData, error) {
+	return c.getAirportFlights(ctx, "GetArrivalsByAirport", "/flights/arrival", airport, begin, end)
+}
 
-	err = response.Body.Close()
+// GetDeparturesByAirport retrieves flights for a certain airport which departed
+// The given time interval must not be larger than seven days!
+func GetDeparturesByAirport(ctx context.Context, airport string, begin int64, end int64) ([]FlighData, error) {
+	client, err := contextClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("response body close %w", err)
+		return nil, err
 	}
 
-	if errRespProcess != nil {
-		return nil, errRespProcess
-	}
-
-	return flighDataList, nil
+	return client.GetDeparturesByAirport(ctx, airport, begin, end)
 }
 
 // GetDeparturesByAirport retrieves flights for a certain airport which departed
 // The given time interval must not be larger than seven days!
-func GetDeparturesByAirport(ctx context.Context, airport string, begin int64, end int64) ([]FlighData, error) {
-	var flighDataList []FlighData
-
-	conn, err := getClient(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("client: %w", err)
-	}
+func (c *Client) GetDeparturesByAirport(ctx context.Context, airport string, begin int64, end int64,
+) ([]FlighData, error) {
+	return c.getAirportFlights(ctx, "GetDeparturesByAirport", "/flights/departure", airport, begin, end)
+}
 
+func (c *Client) getAirportFlights(ctx context.Context, operation string, endpoint string, airport string,
+	begin int64, end int64,
+) ([]FlighData, error) {
 	if airport == "" {
 		return nil, ErrInvalidAirportName
 	}
@@ -114,74 +104,59 @@ func GetDeparturesByAirport(ctx context.Context, airport string, begin int64, en
 
 	requestParams := getFlightsRequestParams(airport, "", begin, end)
 
-	response, err := conn.doGetRequest(ctx, "/flights/departure", requestParams)
-	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
-
-	errRespProcess := response.process(&flighDataList)
-
-	err = response.Body.Close()
-	if err != nil {
-		return nil, fmt.Errorf("response body close %w", err)
-	}
-
-	if errRespProcess != nil {
-		return nil, errRespProcess
-	}
-
-	return flighDataList, nil
+	return c.getFlights(ctx, operation, endpoint, requestParams)
 }
 
 // GetFlightsByInterval retrieves flights for a certain time interval [begin, end].
 // The given time interval must not be larger than two hours!
 func GetFlightsByInterval(ctx context.Context, begin int64, end int64) ([]FlighData, error) {
-	var flighDataList []FlighData
-
-	conn, err := getClient(ctx)
+	client, err := contextClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("client: %w", err)
+		return nil, err
 	}
 
+	return client.GetFlightsByInterval(ctx, begin, end)
+}
+
+// GetFlightsByInterval retrieves flights for a certain time interval [begin, end].
+// The given time interval must not be larger than two hours!
+func (c *Client) GetFlightsByInterval(ctx context.Context, begin int64, end int64) ([]FlighData, error) {
 	if begin <= 0 || end <= 0 {
 		return nil, ErrInvalidUnixTime
 	}
 
 	requestParams := getFlightsRequestParams("", "", begin, end)
 
-	response, err := conn.doGetRequest(ctx, "/flights/all", requestParams)
-	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
-
-	errRespProcess := response.process(&flighDataList)
+	return c.getFlights(ctx, "GetFlightsByInterval", "/flights/all", requestParams)
+}
 
-	err = response.Body.Close()
+// GetFlightsByAircraft retrieves flights for a particular aircraft within a certain time interval.
+// Resulting flights departed and arrived within [begin, end].
+// The given time interval must not be larger than 30 days!
+func GetFlightsByAircraft(ctx context.Context, icao24 string, begin int64, end int64) ([]FlighData, error) {
+	client, err := contextClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("response body close %w", err)
-	}
-
-	if errRespProcess != nil {
-		return nil, errRespProcess
+		return nil, err
 	}
 
-	return flighDataList, nil
+	return client.GetFlightsByAircraft(ctx, icao24, begin, end)
 }
 
 // GetFlightsByAircraft retrieves flights for a particular aircraft within a certain time interval.
 // Resulting flights departed and arrived within [begin, end].
 // The given time interval must not be larger than 30 days!
-func GetFlightsByAircraft(ctx context.Context, icao24 string, begin int64, end int64) ([]FlighData, error) {
-	var flighDataList []FlighData
+func (c *Client) GetFlightsByAircraft(ctx context.Context, icao24 string, begin int64, end int64,
+) ([]FlighData, error) {
+	if icao24 == "" {
+		return nil, ErrInvalidAircraftName
+	}
 
-	conn, err := getClient(ctx)
+	address, err := ParseICAO24(icao24)
 	if err != nil {
-		return nil, fmt.Errorf("client: %w", err)
+		return nil, err
 	}
 
-	if icao24 == "" {
-		return nil, ErrInvalidAircraftName
-	}
+	icao24 = address.String()
 
 	if begin <= 0 || end <= 0 {
 		return nil, ErrInvalidUnixTime
@@ -189,20 +164,21 @@ func GetFlightsByAircraft(ctx context.Context, icao24 string, begin int64, end i
 
 	requestParams := getFlightsRequestParams("", icao24, begin, end)
 
-	response, err := conn.doGetRequest(ctx, "/flights/aircraft", requestParams)
-	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
+	return c.getFlights(ctx, "GetFlightsByAircraft", "/flights/aircraft", requestParams)
+}
 
-	errRespProcess := response.process(&flighDataList)
+func (c *Client) getFlights(ctx context.Context, operation string, endpoint string, requestParams url.Values,
+) ([]FlighData, error) {
+	var flighDataList []FlighData
 
-	err = response.Body.Close()
-	if err != nil {
-		return nil, fmt.Errorf("response body close %w", err)
-	}
+	ctx, call := c.conn.startCall(ctx, operation, endpoint, requestParams)
+
+	err := c.conn.getJSON(ctx, call, &flighDataList)
 
-	if errRespProcess != nil {
-		return nil, errRespProcess
+	call.end(len(flighDataList), err)
+
+	if err != nil {
+		return nil, err
 	}
 
 	return flighDataList, nil
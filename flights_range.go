@@ -0,0 +1,210 @@
+package gopensky
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	defaultRangeConcurrency = 4
+
+	// maximum time interval of the flights API endpoints in seconds.
+	maxAirportFlightsInterval  int64 = 7 * 24 * 60 * 60
+	maxFlightsInterval         int64 = 2 * 60 * 60
+	maxAircraftFlightsInterval int64 = 30 * 24 * 60 * 60
+)
+
+// WithRangeConcurrency sets the maximum number of concurrent requests of the flights range functions
+// (e.g. GetArrivalsByAirportRange) and of GetStatesInRegions. The default is 4.
+func WithRangeConcurrency(workers int) Option {
+	return func(c *Connection) error {
+		if workers < 1 {
+			return fmt.Errorf("%w: %d", errInvalidRangeConcurrency, workers)
+		}
+
+		c.rangeWorkers = workers
+
+		return nil
+	}
+}
+
+// GetArrivalsByAirportRange retrieves flights for a certain airport which arrived
+// within a given time interval [begin, end].
+// The time interval is split into seven days windows which are fetched concurrently.
+func GetArrivalsByAirportRange(ctx context.Context, airport string, begin int64, end int64) ([]FlighData, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetArrivalsByAirportRange(ctx, airport, begin, end)
+}
+
+// GetArrivalsByAirportRange retrieves flights for a certain airport which arrived
+// within a given time interval [begin, end].
+// The time interval is split into seven days windows which are fetched concurrently.
+func (c *Client) GetArrivalsByAirportRange(ctx context.Context, airport string, begin int64, end int64,
+) ([]FlighData, error) {
+	if airport == "" {
+		return nil, ErrInvalidAirportName
+	}
+
+	return c.getFlightsRange(ctx, begin, end, maxAirportFlightsInterval, func(ctx context.Context, begin, end int64,
+	) ([]FlighData, error) {
+		return c.GetArrivalsByAirport(ctx, airport, begin, end)
+	})
+}
+
+// GetDeparturesByAirportRange retrieves flights for a certain airport which departed
+// within a given time interval [begin, end].
+// The time interval is split into seven days windows which are fetched concurrently.
+func GetDeparturesByAirportRange(ctx context.Context, airport string, begin int64, end int64) ([]FlighData, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetDeparturesByAirportRange(ctx, airport, begin, end)
+}
+
+// GetDeparturesByAirportRange retrieves flights for a certain airport which departed
+// within a given time interval [begin, end].
+// The time interval is split into seven days windows which are fetched concurrently.
+func (c *Client) GetDeparturesByAirportRange(ctx context.Context, airport string, begin int64, end int64,
+) ([]FlighData, error) {
+	if airport == "" {
+		return nil, ErrInvalidAirportName
+	}
+
+	return c.getFlightsRange(ctx, begin, end, maxAirportFlightsInterval, func(ctx context.Context, begin, end int64,
+	) ([]FlighData, error) {
+		return c.GetDeparturesByAirport(ctx, airport, begin, end)
+	})
+}
+
+// GetFlightsByIntervalRange retrieves flights for a certain time interval [begin, end].
+// The time interval is split into two hours windows which are fetched concurrently.
+func GetFlightsByIntervalRange(ctx context.Context, begin int64, end int64) ([]FlighData, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetFlightsByIntervalRange(ctx, begin, end)
+}
+
+// GetFlightsByIntervalRange retrieves flights for a certain time interval [begin, end].
+// The time interval is split into two hours windows which are fetched concurrently.
+func (c *Client) GetFlightsByIntervalRange(ctx context.Context, begin int64, end int64) ([]FlighData, error) {
+	return c.getFlightsRange(ctx, begin, end, maxFlightsInterval, c.GetFlightsByInterval)
+}
+
+// GetFlightsByAircraftRange retrieves flights for a particular aircraft within a certain time interval [begin, end].
+// The time interval is split into 30 days windows which are fetched concurrently.
+func GetFlightsByAircraftRange(ctx context.Context, icao24 string, begin int64, end int64) ([]FlighData, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetFlightsByAircraftRange(ctx, icao24, begin, end)
+}
+
+// GetFlightsByAircraftRange retrieves flights for a particular aircraft within a certain time interval [begin, end].
+// The time interval is split into 30 days windows which are fetched concurrently.
+func (c *Client) GetFlightsByAircraftRange(ctx context.Context, icao24 string, begin int64, end int64,
+) ([]FlighData, error) {
+	if icao24 == "" {
+		return nil, ErrInvalidAircraftName
+	}
+
+	address, err := ParseICAO24(icao24)
+	if err != nil {
+		return nil, err
+	}
+
+	icao24 = address.String()
+
+	return c.getFlightsRange(ctx, begin, end, maxAircraftFlightsInterval, func(ctx context.Context, begin, end int64,
+	) ([]FlighData, error) {
+		return c.GetFlightsByAircraft(ctx, icao24, begin, end)
+	})
+}
+
+type flightsFetchFunc func(ctx context.Context, begin int64, end int64) ([]FlighData, error)
+
+// getFlightsRange fetches the flights of the [begin, end] windows with bounded concurrency,
+// and returns the de-duplicated flights sorted by their first seen time.
+// The windows without flights (404 Not Found) are ignored.
+func (c *Client) getFlightsRange(ctx context.Context, begin int64, end int64, window int64, fetch flightsFetchFunc,
+) ([]FlighData, error) {
+	if begin <= 0 || end <= 0 || end < begin {
+		return nil, ErrInvalidUnixTime
+	}
+
+	windows := splitTimeRange(begin, end, window)
+
+	results, err := fetchAll(ctx, len(windows), c.conn.rangeWorkers, func(ctx context.Context, index int,
+	) ([]FlighData, error) {
+		flights, err := fetch(ctx, windows[index][0], windows[index][1])
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("flights [%d, %d]: %w", windows[index][0], windows[index][1], err)
+		}
+
+		return flights, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeFlights(results), nil
+}
+
+// splitTimeRange splits the [begin, end] time range into consecutive windows not larger than window.
+func splitTimeRange(begin int64, end int64, window int64) [][2]int64 {
+	var windows [][2]int64
+
+	for windowBegin := begin; ; windowBegin += window {
+		windowEnd := min(windowBegin+window, end)
+		windows = append(windows, [2]int64{windowBegin, windowEnd})
+
+		if windowEnd >= end {
+			return windows
+		}
+	}
+}
+
+// mergeFlights merges the flights lists, removes the flights seen in several windows
+// and sorts them by first seen time.
+func mergeFlights(flightsLists [][]FlighData) []FlighData {
+	type flightKey struct {
+		icao24    string
+		firstSeen int64
+		lastSeen  int64
+	}
+
+	seen := make(map[flightKey]struct{})
+	merged := make([]FlighData, 0)
+
+	for _, flights := range flightsLists {
+		for _, flight := range flights {
+			key := flightKey{icao24: flight.Icao24, firstSeen: flight.FirstSeen, lastSeen: flight.LastSeen}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+
+			seen[key] = struct{}{}
+
+			merged = append(merged, flight)
+		}
+	}
+
+	slices.SortStableFunc(merged, func(a, b FlighData) int {
+		return cmp.Or(cmp.Compare(a.FirstSeen, b.FirstSeen), cmp.Compare(a.Icao24, b.Icao24))
+	})
+
+	return merged
+}
@@ -0,0 +1,150 @@
+package gopensky_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Flights range", func() {
+	Describe("splitTimeRange", func() {
+		It("splits the time range into windows", func() {
+			Expect(gopensky.SplitTimeRange(100, 350, 100)).To(Equal([][2]int64{
+				{100, 200}, {200, 300}, {300, 350},
+			}))
+
+			Expect(gopensky.SplitTimeRange(100, 200, 100)).To(Equal([][2]int64{{100, 200}}))
+			Expect(gopensky.SplitTimeRange(100, 100, 100)).To(Equal([][2]int64{{100, 100}}))
+		})
+	})
+
+	Describe("mergeFlights", func() {
+		It("removes the duplicated flights and sorts them", func() {
+			flights := gopensky.MergeFlights([][]gopensky.FlighData{
+				{
+					{Icao24: "c060b9", FirstSeen: 300, LastSeen: 400},
+					{Icao24: "4b1806", FirstSeen: 100, LastSeen: 200},
+				},
+				{
+					{Icao24: "c060b9", FirstSeen: 300, LastSeen: 400},
+					{Icao24: "3c6444", FirstSeen: 100, LastSeen: 250},
+				},
+			})
+
+			Expect(flights).To(Equal([]gopensky.FlighData{
+				{Icao24: "3c6444", FirstSeen: 100, LastSeen: 250},
+				{Icao24: "4b1806", FirstSeen: 100, LastSeen: 200},
+				{Icao24: "c060b9", FirstSeen: 300, LastSeen: 400},
+			}))
+		})
+	})
+
+	Describe("GetFlightsByIntervalRange", func() {
+		var (
+			mu       sync.Mutex
+			windows  [][2]int64
+			active   int
+			maxCalls int
+		)
+
+		newServer := func(handler func(w http.ResponseWriter, begin int64, end int64)) *httptest.Server {
+			mu.Lock()
+			windows = nil
+			active = 0
+			maxCalls = 0
+			mu.Unlock()
+
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				begin, _ := strconv.ParseInt(r.URL.Query().Get("begin"), 10, 64)
+				end, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+
+				mu.Lock()
+				windows = append(windows, [2]int64{begin, end})
+				active++
+				maxCalls = max(maxCalls, active)
+				mu.Unlock()
+
+				handler(w, begin, end)
+
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}))
+		}
+
+		It("fetches the windows and merges the flights", func() {
+			server := newServer(func(w http.ResponseWriter, begin int64, end int64) {
+				if begin == 1696007200 {
+					w.WriteHeader(http.StatusNotFound)
+
+					return
+				}
+
+				// the straddling flight is returned by the windows on both sides.
+				fmt.Fprintf(w, `[{"icao24": "c060b9", "firstSeen": %d, "lastSeen": %d},
+					{"icao24": "4b1806", "firstSeen": 1696007000, "lastSeen": 1696007300}]`, begin, end)
+			})
+			defer server.Close()
+
+			client, err := gopensky.NewClient("", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithRangeConcurrency(2),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			flights, err := client.GetFlightsByIntervalRange(context.Background(), 1696000000, 1696016000)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(windows).To(ConsistOf(
+				[2]int64{1696000000, 1696007200},
+				[2]int64{1696007200, 1696014400},
+				[2]int64{1696014400, 1696016000},
+			))
+			Expect(maxCalls).To(BeNumerically("<=", 2))
+
+			Expect(flights).To(HaveLen(3))
+			Expect(flights[0].FirstSeen).To(Equal(int64(1696000000)))
+			Expect(flights[1].Icao24).To(Equal("4b1806"))
+			Expect(flights[2].FirstSeen).To(Equal(int64(1696014400)))
+		})
+
+		It("returns the windows errors", func() {
+			server := newServer(func(w http.ResponseWriter, _ int64, _ int64) {
+				w.WriteHeader(http.StatusForbidden)
+			})
+			defer server.Close()
+
+			client, err := gopensky.NewClient("", "", gopensky.WithBaseURL(server.URL))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetFlightsByIntervalRange(context.Background(), 1696000000, 1696016000)
+			Expect(err).To(MatchError(gopensky.ErrUnauthorized))
+			Expect(err.Error()).To(HavePrefix("flights ["))
+		})
+
+		It("validates the parameters", func() {
+			client, err := gopensky.NewClient("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetFlightsByIntervalRange(context.Background(), 1696016000, 1696000000)
+			Expect(err).To(MatchError(gopensky.ErrInvalidUnixTime))
+
+			_, err = client.GetArrivalsByAirportRange(context.Background(), "", 1696000000, 1696016000)
+			Expect(err).To(MatchError(gopensky.ErrInvalidAirportName))
+
+			_, err = client.GetFlightsByAircraftRange(context.Background(), "", 1696000000, 1696016000)
+			Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+
+			_, err = gopensky.NewClient("", "", gopensky.WithRangeConcurrency(0))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
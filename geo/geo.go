@@ -0,0 +1,151 @@
+/*
+Package geo provides the great-circle computations on the WGS-84 coordinates used by the OpenSky API
+(decimal degrees), the distances are in meters.
+
+	// all the positions within 50 nautical miles of JFK airport
+	box := geo.BoundingBoxAround(40.6413, -73.7781, 50*units.MetersPerNauticalMile)
+*/
+package geo
+
+import "math"
+
+const (
+	// EarthRadius is the mean Earth radius in meters.
+	EarthRadius = 6371008.8
+
+	maxLatitude  = 90.0
+	maxLongitude = 180.0
+	halfCircle   = 180.0
+	fullCircle   = 360.0
+)
+
+// BoundingBox is a WGS-84 latitude/longitude box in decimal degrees.
+// The box crosses the antimeridian if MinLongitude is greater than MaxLongitude.
+type BoundingBox struct {
+	MinLatitude  float64 `json:"minLatitude"`
+	MinLongitude float64 `json:"minLongitude"`
+	MaxLatitude  float64 `json:"maxLatitude"`
+	MaxLongitude float64 `json:"maxLongitude"`
+}
+
+// Distance returns the great-circle (haversine) distance in meters between two positions.
+func Distance(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	deltaPhi := phi2 - phi1
+	deltaLambda := radians(lon2 - lon1)
+
+	a := math.Sin(deltaPhi/2)*math.Sin(deltaPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(deltaLambda/2)*math.Sin(deltaLambda/2)
+
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Bearing returns the initial great-circle bearing in decimal degrees clockwise from north [0, 360)
+// to go from the first position to the second one.
+func Bearing(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	deltaLambda := radians(lon2 - lon1)
+
+	y := math.Sin(deltaLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(deltaLambda)
+
+	return normalizeBearing(degrees(math.Atan2(y, x)))
+}
+
+// Destination returns the position reached from the start position after travelling the distance in meters
+// along the great-circle with the initial bearing in decimal degrees.
+func Destination(lat float64, lon float64, bearing float64, distance float64) (float64, float64) {
+	phi1, lambda1 := radians(lat), radians(lon)
+	theta := radians(bearing)
+	delta := distance / EarthRadius
+
+	phi2 := math.Asin(math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1),
+		math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+
+	return degrees(phi2), NormalizeLongitude(degrees(lambda2))
+}
+
+// BoundingBoxAround returns the smallest bounding box containing all the positions within the radius in meters
+// of the center position. The box crosses the antimeridian (MinLongitude > MaxLongitude) if the circle does,
+// and it covers all the longitudes if the circle contains a pole.
+func BoundingBoxAround(lat float64, lon float64, radius float64) BoundingBox {
+	delta := radius / EarthRadius
+	phi := radians(lat)
+
+	box := BoundingBox{
+		MinLatitude: degrees(phi - delta),
+		MaxLatitude: degrees(phi + delta),
+	}
+
+	if box.MinLatitude <= -maxLatitude || box.MaxLatitude >= maxLatitude {
+		box.MinLatitude = math.Max(box.MinLatitude, -maxLatitude)
+		box.MaxLatitude = math.Min(box.MaxLatitude, maxLatitude)
+		box.MinLongitude = -maxLongitude
+		box.MaxLongitude = maxLongitude
+
+		return box
+	}
+
+	deltaLambda := degrees(math.Asin(math.Sin(delta) / math.Cos(phi)))
+	if deltaLambda >= maxLongitude {
+		box.MinLongitude = -maxLongitude
+		box.MaxLongitude = maxLongitude
+
+		return box
+	}
+
+	box.MinLongitude = NormalizeLongitude(lon - deltaLambda)
+	box.MaxLongitude = NormalizeLongitude(lon + deltaLambda)
+
+	return box
+}
+
+// CrossesAntimeridian returns true if the box crosses the ±180° longitude.
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.MinLongitude > b.MaxLongitude
+}
+
+// Contains returns true if the position is within the box.
+func (b BoundingBox) Contains(lat float64, lon float64) bool {
+	if lat < b.MinLatitude || lat > b.MaxLatitude {
+		return false
+	}
+
+	if b.CrossesAntimeridian() {
+		return lon >= b.MinLongitude || lon <= b.MaxLongitude
+	}
+
+	return lon >= b.MinLongitude && lon <= b.MaxLongitude
+}
+
+// NormalizeLongitude returns the longitude in the [-180, 180] range.
+func NormalizeLongitude(lon float64) float64 {
+	if lon >= -maxLongitude && lon <= maxLongitude {
+		return lon
+	}
+
+	lon = math.Mod(lon+maxLongitude, fullCircle)
+	if lon < 0 {
+		lon += fullCircle
+	}
+
+	return lon - maxLongitude
+}
+
+func normalizeBearing(bearing float64) float64 {
+	bearing = math.Mod(bearing, fullCircle)
+	if bearing < 0 {
+		bearing += fullCircle
+	}
+
+	return bearing
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / halfCircle
+}
+
+func degrees(rad float64) float64 {
+	return rad * halfCircle / math.Pi
+}
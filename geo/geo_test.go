@@ -0,0 +1,89 @@
+package geo_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky/geo"
+)
+
+const (
+	jfkLat, jfkLon = 40.6413, -73.7781
+	lhrLat, lhrLon = 51.4700, -0.4543
+)
+
+var _ = Describe("Geo", func() {
+	It("computes the great-circle distance", func() {
+		Expect(geo.Distance(jfkLat, jfkLon, lhrLat, lhrLon)).To(BeNumerically("~", 5540000, 5000))
+		Expect(geo.Distance(jfkLat, jfkLon, jfkLat, jfkLon)).To(BeZero())
+
+		// one degree of latitude is ~60 nautical miles
+		Expect(geo.Distance(0, 0, 1, 0)).To(BeNumerically("~", 111195, 1))
+
+		// across the antimeridian
+		Expect(geo.Distance(0, 179.5, 0, -179.5)).To(BeNumerically("~", 111195, 1))
+	})
+
+	It("computes the initial bearing", func() {
+		Expect(geo.Bearing(0, 0, 1, 0)).To(BeNumerically("~", 0, 1e-9))
+		Expect(geo.Bearing(0, 0, 0, 1)).To(BeNumerically("~", 90, 1e-9))
+		Expect(geo.Bearing(0, 0, -1, 0)).To(BeNumerically("~", 180, 1e-9))
+		Expect(geo.Bearing(0, 0, 0, -1)).To(BeNumerically("~", 270, 1e-9))
+		Expect(geo.Bearing(jfkLat, jfkLon, lhrLat, lhrLon)).To(BeNumerically("~", 51.4, 0.5))
+	})
+
+	It("computes the destination point", func() {
+		lat, lon := geo.Destination(jfkLat, jfkLon, 51.4, geo.Distance(jfkLat, jfkLon, lhrLat, lhrLon))
+		Expect(geo.Distance(lat, lon, lhrLat, lhrLon)).To(BeNumerically("<", 50000))
+
+		lat, lon = geo.Destination(0, 179.5, 90, 111195)
+		Expect(lat).To(BeNumerically("~", 0, 1e-9))
+		Expect(lon).To(BeNumerically("~", -179.5, 1e-3))
+
+		lat, lon = geo.Destination(jfkLat, jfkLon, 123, 0)
+		Expect(lat).To(BeNumerically("~", jfkLat, 1e-9))
+		Expect(lon).To(BeNumerically("~", jfkLon, 1e-9))
+	})
+
+	It("returns the bounding box around a position", func() {
+		radius := 50 * 1852.0
+		box := geo.BoundingBoxAround(jfkLat, jfkLon, radius)
+
+		Expect(box.CrossesAntimeridian()).To(BeFalse())
+		Expect(box.MaxLatitude - jfkLat).To(BeNumerically("~", 0.8327, 1e-3))
+		Expect(jfkLat - box.MinLatitude).To(BeNumerically("~", 0.8327, 1e-3))
+		Expect(box.MaxLongitude - jfkLon).To(BeNumerically(">", 0.8327))
+		Expect(box.Contains(jfkLat, jfkLon)).To(BeTrue())
+
+		for bearing := 0.0; bearing < 360; bearing += 15 {
+			lat, lon := geo.Destination(jfkLat, jfkLon, bearing, radius*0.999)
+			Expect(box.Contains(lat, lon)).To(BeTrue(), "bearing %v", bearing)
+		}
+
+		Expect(box.Contains(lhrLat, lhrLon)).To(BeFalse())
+	})
+
+	It("returns the bounding boxes crossing the antimeridian or containing a pole", func() {
+		box := geo.BoundingBoxAround(60, 179.9, 100000)
+		Expect(box.CrossesAntimeridian()).To(BeTrue())
+		Expect(box.MinLongitude).To(BeNumerically(">", 170))
+		Expect(box.MaxLongitude).To(BeNumerically("<", -170))
+		Expect(box.Contains(60, -179.9)).To(BeTrue())
+		Expect(box.Contains(60, 179.95)).To(BeTrue())
+		Expect(box.Contains(60, 0)).To(BeFalse())
+
+		box = geo.BoundingBoxAround(89.5, 10, 100000)
+		Expect(box).To(Equal(geo.BoundingBox{
+			MinLatitude: box.MinLatitude, MinLongitude: -180, MaxLatitude: 90, MaxLongitude: 180,
+		}))
+		Expect(box.MinLatitude).To(BeNumerically("~", 88.6, 0.1))
+	})
+
+	It("normalizes the longitudes", func() {
+		Expect(geo.NormalizeLongitude(10)).To(Equal(10.0))
+		Expect(geo.NormalizeLongitude(180)).To(Equal(180.0))
+		Expect(geo.NormalizeLongitude(190)).To(Equal(-170.0))
+		Expect(geo.NormalizeLongitude(-190)).To(Equal(170.0))
+		Expect(geo.NormalizeLongitude(540)).To(Equal(-180.0))
+	})
+})
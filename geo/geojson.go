@@ -0,0 +1,125 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const minPositionValues = 2
+
+// geoJSON is a GeoJSON object, a geometry, a feature or a feature collection.
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSON        `json:"geometry"`
+	Geometries  []geoJSON       `json:"geometries"`
+	Features    []geoJSON       `json:"features"`
+}
+
+// ParseGeoJSON parses the Polygon and MultiPolygon geometries of a GeoJSON geometry, feature,
+// feature collection or geometry collection. The other geometries types are ignored.
+func ParseGeoJSON(data []byte) (MultiPolygon, error) {
+	var object geoJSON
+
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("%w: geojson: %w", ErrInvalidGeometry, err)
+	}
+
+	polygons, err := object.polygons()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := polygons.Validate(); err != nil {
+		return nil, err
+	}
+
+	return polygons, nil
+}
+
+func (g *geoJSON) polygons() (MultiPolygon, error) {
+	switch g.Type {
+	case "Polygon":
+		var coordinates [][][]float64
+
+		if err := json.Unmarshal(g.Coordinates, &coordinates); err != nil {
+			return nil, fmt.Errorf("%w: geojson polygon: %w", ErrInvalidGeometry, err)
+		}
+
+		polygon, err := geoJSONPolygon(coordinates)
+		if err != nil {
+			return nil, err
+		}
+
+		return MultiPolygon{polygon}, nil
+	case "MultiPolygon":
+		var coordinates [][][][]float64
+
+		if err := json.Unmarshal(g.Coordinates, &coordinates); err != nil {
+			return nil, fmt.Errorf("%w: geojson multipolygon: %w", ErrInvalidGeometry, err)
+		}
+
+		polygons := make(MultiPolygon, 0, len(coordinates))
+
+		for _, polygonCoordinates := range coordinates {
+			polygon, err := geoJSONPolygon(polygonCoordinates)
+			if err != nil {
+				return nil, err
+			}
+
+			polygons = append(polygons, polygon)
+		}
+
+		return polygons, nil
+	case "Feature":
+		if g.Geometry == nil {
+			return nil, nil
+		}
+
+		return g.Geometry.polygons()
+	case "FeatureCollection":
+		return collectPolygons(g.Features)
+	case "GeometryCollection":
+		return collectPolygons(g.Geometries)
+	case "":
+		return nil, fmt.Errorf("%w: geojson: missing type", ErrInvalidGeometry)
+	}
+
+	return nil, nil
+}
+
+func collectPolygons(objects []geoJSON) (MultiPolygon, error) {
+	var polygons MultiPolygon
+
+	for index := range objects {
+		objectPolygons, err := objects[index].polygons()
+		if err != nil {
+			return nil, err
+		}
+
+		polygons = append(polygons, objectPolygons...)
+	}
+
+	return polygons, nil
+}
+
+// geoJSONPolygon converts the [longitude, latitude] positions of the polygon rings.
+func geoJSONPolygon(coordinates [][][]float64) (Polygon, error) {
+	polygon := make(Polygon, 0, len(coordinates))
+
+	for _, ringCoordinates := range coordinates {
+		ring := make(Ring, 0, len(ringCoordinates))
+
+		for _, position := range ringCoordinates {
+			if len(position) < minPositionValues {
+				return nil, fmt.Errorf("%w: geojson: invalid position %v", ErrInvalidGeometry, position)
+			}
+
+			ring = append(ring, Point{Latitude: position[1], Longitude: position[0]})
+		}
+
+		polygon = append(polygon, ring)
+	}
+
+	return polygon, nil
+}
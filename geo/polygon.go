@@ -0,0 +1,137 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const minRingPoints = 3
+
+var (
+	// ErrInvalidGeometry is returned for invalid or unsupported GeoJSON and WKT geometries.
+	ErrInvalidGeometry = errors.New("invalid geometry")
+
+	errRingPoints = errors.New("ring has less than 3 points")
+)
+
+// Point is a WGS-84 position in decimal degrees.
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Ring is a closed line of positions, the last point may repeat the first one.
+type Ring []Point
+
+// Polygon is an exterior ring followed by the optional interior rings (holes).
+// The polygon edges are straight lines in the latitude/longitude plane, they must not cross the antimeridian.
+type Polygon []Ring
+
+// MultiPolygon is a set of polygons.
+type MultiPolygon []Polygon
+
+// Contains returns true if the position is within the ring (even-odd rule).
+func (r Ring) Contains(lat float64, lon float64) bool {
+	inside := false
+
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		pi, pj := r[i], r[j]
+
+		if (pi.Latitude > lat) != (pj.Latitude > lat) &&
+			lon < (pj.Longitude-pi.Longitude)*(lat-pi.Latitude)/(pj.Latitude-pi.Latitude)+pi.Longitude {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Contains returns true if the position is within the exterior ring and outside of the holes.
+func (p Polygon) Contains(lat float64, lon float64) bool {
+	if len(p) == 0 || !p[0].Contains(lat, lon) {
+		return false
+	}
+
+	for _, hole := range p[1:] {
+		if hole.Contains(lat, lon) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Contains returns true if the position is within one of the polygons.
+func (m MultiPolygon) Contains(lat float64, lon float64) bool {
+	for _, polygon := range m {
+		if polygon.Contains(lat, lon) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Bounds returns the minimal bounding box enclosing the polygons exterior rings.
+func (m MultiPolygon) Bounds() BoundingBox {
+	box := BoundingBox{
+		MinLatitude:  math.Inf(1),
+		MinLongitude: math.Inf(1),
+		MaxLatitude:  math.Inf(-1),
+		MaxLongitude: math.Inf(-1),
+	}
+
+	for _, polygon := range m {
+		if len(polygon) == 0 {
+			continue
+		}
+
+		for _, point := range polygon[0] {
+			box.MinLatitude = math.Min(box.MinLatitude, point.Latitude)
+			box.MinLongitude = math.Min(box.MinLongitude, point.Longitude)
+			box.MaxLatitude = math.Max(box.MaxLatitude, point.Latitude)
+			box.MaxLongitude = math.Max(box.MaxLongitude, point.Longitude)
+		}
+	}
+
+	if math.IsInf(box.MinLatitude, 1) {
+		return BoundingBox{}
+	}
+
+	return box
+}
+
+// Validate checks that the polygons rings have at least 3 points and valid coordinates.
+func (m MultiPolygon) Validate() error {
+	if len(m) == 0 {
+		return fmt.Errorf("%w: no polygon", ErrInvalidGeometry)
+	}
+
+	for polygonIndex, polygon := range m {
+		if len(polygon) == 0 {
+			return fmt.Errorf("%w: polygon %d: no ring", ErrInvalidGeometry, polygonIndex)
+		}
+
+		for ringIndex, ring := range polygon {
+			points := len(ring)
+			if points > 0 && ring[0] == ring[points-1] {
+				points--
+			}
+
+			if points < minRingPoints {
+				return fmt.Errorf("%w: polygon %d ring %d: %w", ErrInvalidGeometry, polygonIndex, ringIndex, errRingPoints)
+			}
+
+			for _, point := range ring {
+				if !(point.Latitude >= -maxLatitude && point.Latitude <= maxLatitude) ||
+					!(point.Longitude >= -maxLongitude && point.Longitude <= maxLongitude) {
+					return fmt.Errorf("%w: polygon %d ring %d: invalid position (%g, %g)",
+						ErrInvalidGeometry, polygonIndex, ringIndex, point.Latitude, point.Longitude)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package geo_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky/geo"
+)
+
+var _ = Describe("Polygon", func() {
+	square := geo.Ring{
+		{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 10},
+		{Latitude: 10, Longitude: 10}, {Latitude: 10, Longitude: 0}, {Latitude: 0, Longitude: 0},
+	}
+	hole := geo.Ring{
+		{Latitude: 4, Longitude: 4}, {Latitude: 4, Longitude: 6}, {Latitude: 6, Longitude: 6}, {Latitude: 6, Longitude: 4},
+	}
+	triangle := geo.Ring{{Latitude: 20, Longitude: 20}, {Latitude: 20, Longitude: 30}, {Latitude: 30, Longitude: 20}}
+
+	It("tests the positions within the polygons", func() {
+		polygon := geo.Polygon{square, hole}
+		Expect(polygon.Contains(1, 1)).To(BeTrue())
+		Expect(polygon.Contains(5, 5)).To(BeFalse())
+		Expect(polygon.Contains(11, 5)).To(BeFalse())
+		Expect(polygon.Contains(5, -1)).To(BeFalse())
+		Expect(geo.Polygon{}.Contains(1, 1)).To(BeFalse())
+
+		multi := geo.MultiPolygon{polygon, {triangle}}
+		Expect(multi.Contains(1, 1)).To(BeTrue())
+		Expect(multi.Contains(22, 22)).To(BeTrue())
+		Expect(multi.Contains(29, 29)).To(BeFalse())
+	})
+
+	It("returns the polygons bounds", func() {
+		multi := geo.MultiPolygon{{square, hole}, {triangle}}
+		Expect(multi.Bounds()).To(Equal(geo.BoundingBox{MinLatitude: 0, MinLongitude: 0, MaxLatitude: 30, MaxLongitude: 30}))
+		Expect(geo.MultiPolygon{}.Bounds()).To(Equal(geo.BoundingBox{}))
+	})
+
+	It("validates the polygons", func() {
+		Expect(geo.MultiPolygon{{square, hole}, {triangle}}.Validate()).To(Succeed())
+		Expect(geo.MultiPolygon{}.Validate()).To(MatchError(geo.ErrInvalidGeometry))
+		Expect(geo.MultiPolygon{{}}.Validate()).To(MatchError(geo.ErrInvalidGeometry))
+
+		err := geo.MultiPolygon{{square[3:]}}.Validate()
+		Expect(err).To(MatchError(geo.ErrInvalidGeometry))
+		Expect(err.Error()).To(Equal("invalid geometry: polygon 0 ring 0: ring has less than 3 points"))
+
+		err = geo.MultiPolygon{{geo.Ring{{Latitude: 91}, {Latitude: 1}, {Longitude: 1}}}}.Validate()
+		Expect(err).To(MatchError(geo.ErrInvalidGeometry))
+	})
+
+	It("parses the GeoJSON geometries", func() {
+		polygons, err := geo.ParseGeoJSON([]byte(`{"type": "Polygon", "coordinates": [
+			[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+			[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+		]}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(polygons).To(HaveLen(1))
+		Expect(polygons[0]).To(HaveLen(2))
+		Expect(polygons[0][0][1]).To(Equal(geo.Point{Latitude: 0, Longitude: 10}))
+		Expect(polygons.Contains(1, 1)).To(BeTrue())
+		Expect(polygons.Contains(5, 5)).To(BeFalse())
+
+		polygons, err = geo.ParseGeoJSON([]byte(`{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "properties": {"name": "A"}, "geometry": {"type": "MultiPolygon", "coordinates": [
+				[[[0, 0], [10, 0], [10, 10], [0, 0]]],
+				[[[20, 20], [30, 20], [20, 30], [20, 20]]]
+			]}},
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 1]}},
+			{"type": "Feature", "geometry": null}
+		]}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(polygons).To(HaveLen(2))
+
+		for _, invalid := range []string{
+			`{`,
+			`{"coordinates": []}`,
+			`{"type": "Point", "coordinates": [1, 1]}`,
+			`{"type": "Polygon", "coordinates": [[[0, 0], [10, 0]]]}`,
+			`{"type": "Polygon", "coordinates": [[[0], [10, 0], [10, 10]]]}`,
+			`{"type": "MultiPolygon", "coordinates": [[0, 0]]}`,
+		} {
+			_, err = geo.ParseGeoJSON([]byte(invalid))
+			Expect(err).To(MatchError(geo.ErrInvalidGeometry), invalid)
+		}
+	})
+
+	It("parses the WKT geometries", func() {
+		polygons, err := geo.ParseWKT("POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (4 4, 6 4, 6 6, 4 6, 4 4))")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(polygons).To(HaveLen(1))
+		Expect(polygons[0]).To(HaveLen(2))
+		Expect(polygons[0][0][1]).To(Equal(geo.Point{Latitude: 0, Longitude: 10}))
+		Expect(polygons.Contains(1, 1)).To(BeTrue())
+		Expect(polygons.Contains(5, 5)).To(BeFalse())
+
+		polygons, err = geo.ParseWKT("multipolygon Z (((0 0 100, 10 0 100, 10 10 100, 0 0 100)), ((20 20 0,30 20 0,20 30 0,20 20 0)))")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(polygons).To(HaveLen(2))
+		Expect(polygons[1][0][2]).To(Equal(geo.Point{Latitude: 30, Longitude: 20}))
+
+		polygons, err = geo.ParseWKT("POLYGON((-1.5e1 -2.5, 1 -2.5, 1 2.5, -15 -2.5))")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(polygons[0][0][0]).To(Equal(geo.Point{Latitude: -2.5, Longitude: -15}))
+
+		for _, invalid := range []string{
+			"",
+			"POINT (1 1)",
+			"POLYGON EMPTY",
+			"POLYGON ((0 0, 10 0, 10 10, 0 0)",
+			"POLYGON ((0 0, 10 0, 10 10, 0 0)) extra",
+			"POLYGON ((0 0, 10, 10 10, 0 0))",
+			"POLYGON ((0 0, 10 0, 0 0))",
+			"POLYGON ((0 0, 1-0 0, 10 10, 0 0))",
+		} {
+			_, err = geo.ParseWKT(invalid)
+			Expect(err).To(MatchError(geo.ErrInvalidGeometry), invalid)
+		}
+	})
+})
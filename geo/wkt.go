@@ -0,0 +1,178 @@
+package geo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseWKT parses a POLYGON or MULTIPOLYGON well-known text geometry (e.g. POLYGON ((lon lat, lon lat, ...))).
+// The Z and M values are ignored.
+func ParseWKT(text string) (MultiPolygon, error) {
+	scanner := &wktScanner{text: strings.TrimSpace(text)}
+	geometryType := strings.ToUpper(scanner.readWord())
+
+	for _, dimension := range []string{"Z", "M", "ZM"} {
+		if strings.EqualFold(scanner.peekWord(), dimension) {
+			scanner.readWord()
+		}
+	}
+
+	var (
+		polygons MultiPolygon
+		err      error
+	)
+
+	switch geometryType {
+	case "POLYGON":
+		var polygon Polygon
+
+		polygon, err = scanner.readPolygon()
+		polygons = MultiPolygon{polygon}
+	case "MULTIPOLYGON":
+		err = scanner.readList(func() error {
+			polygon, err := scanner.readPolygon()
+			polygons = append(polygons, polygon)
+
+			return err
+		})
+	default:
+		return nil, fmt.Errorf("%w: wkt: unsupported geometry %q", ErrInvalidGeometry, geometryType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if scanner.skipSpaces(); scanner.pos < len(scanner.text) {
+		return nil, scanner.errorf("unexpected %q", scanner.text[scanner.pos:])
+	}
+
+	if err := polygons.Validate(); err != nil {
+		return nil, err
+	}
+
+	return polygons, nil
+}
+
+type wktScanner struct {
+	text string
+	pos  int
+}
+
+func (s *wktScanner) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: wkt: offset %d: %s", ErrInvalidGeometry, s.pos, fmt.Sprintf(format, args...))
+}
+
+func (s *wktScanner) skipSpaces() {
+	for s.pos < len(s.text) && unicode.IsSpace(rune(s.text[s.pos])) {
+		s.pos++
+	}
+}
+
+func (s *wktScanner) peekWord() string {
+	start := s.pos
+	word := s.readWord()
+	s.pos = start
+
+	return word
+}
+
+func (s *wktScanner) readWord() string {
+	s.skipSpaces()
+
+	start := s.pos
+	for s.pos < len(s.text) && unicode.IsLetter(rune(s.text[s.pos])) {
+		s.pos++
+	}
+
+	return s.text[start:s.pos]
+}
+
+func (s *wktScanner) expect(char byte) error {
+	s.skipSpaces()
+
+	if s.pos >= len(s.text) || s.text[s.pos] != char {
+		return s.errorf("expected %q", char)
+	}
+
+	s.pos++
+
+	return nil
+}
+
+// readList reads a parenthesized comma separated list of items.
+func (s *wktScanner) readList(readItem func() error) error {
+	if err := s.expect('('); err != nil {
+		return err
+	}
+
+	for {
+		if err := readItem(); err != nil {
+			return err
+		}
+
+		s.skipSpaces()
+
+		if s.pos < len(s.text) && s.text[s.pos] == ',' {
+			s.pos++
+
+			continue
+		}
+
+		return s.expect(')')
+	}
+}
+
+func (s *wktScanner) readPolygon() (Polygon, error) {
+	var polygon Polygon
+
+	err := s.readList(func() error {
+		var ring Ring
+
+		err := s.readList(func() error {
+			point, err := s.readPoint()
+			ring = append(ring, point)
+
+			return err
+		})
+
+		polygon = append(polygon, ring)
+
+		return err
+	})
+
+	return polygon, err
+}
+
+// readPoint reads the longitude and latitude of a position, the other values are ignored.
+func (s *wktScanner) readPoint() (Point, error) {
+	var values []float64
+
+	for {
+		s.skipSpaces()
+
+		start := s.pos
+		for s.pos < len(s.text) && strings.IndexByte("+-.0123456789eE", s.text[s.pos]) >= 0 {
+			s.pos++
+		}
+
+		if start == s.pos {
+			break
+		}
+
+		value, err := strconv.ParseFloat(s.text[start:s.pos], 64)
+		if err != nil {
+			return Point{}, s.errorf("invalid number %q", s.text[start:s.pos])
+		}
+
+		values = append(values, value)
+	}
+
+	if len(values) < minPositionValues {
+		return Point{}, s.errorf("invalid position")
+	}
+
+	return Point{Latitude: values[1], Longitude: values[0]}, nil
+}
@@ -0,0 +1,140 @@
+package gopensky
+
+import (
+	"github.com/navidys/gopensky/geo"
+)
+
+// Geofence is an area defined by polygons with optional floor and ceiling altitudes.
+type Geofence struct {
+	// Polygons of the area.
+	Area geo.MultiPolygon `json:"area"`
+
+	// Lowest altitude in meters of the area. Can be nil.
+	Floor *float64 `json:"floor"`
+
+	// Highest altitude in meters of the area. Can be nil.
+	Ceiling *float64 `json:"ceiling"`
+}
+
+// GeofenceVisit is a stay of a flight track in a geofence.
+type GeofenceVisit struct {
+	// Time of the first waypoint in the geofence in seconds since epoch (Unix time).
+	Entry int64 `json:"entry"`
+
+	// Time of the first waypoint out of the geofence in seconds since epoch (Unix time).
+	// It's 0 if the track ends in the geofence.
+	Exit int64 `json:"exit"`
+}
+
+// NewGeofence returns a new geofence of the polygons without altitude limits.
+func NewGeofence(polygons ...geo.Polygon) (*Geofence, error) {
+	area := geo.MultiPolygon(polygons)
+	if err := area.Validate(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &Geofence{Area: area}, nil
+}
+
+// NewGeofenceFromGeoJSON returns a new geofence of the GeoJSON Polygon and MultiPolygon geometries.
+func NewGeofenceFromGeoJSON(data []byte) (*Geofence, error) {
+	area, err := geo.ParseGeoJSON(data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &Geofence{Area: area}, nil
+}
+
+// NewGeofenceFromWKT returns a new geofence of the POLYGON or MULTIPOLYGON well-known text geometry.
+func NewGeofenceFromWKT(text string) (*Geofence, error) {
+	area, err := geo.ParseWKT(text)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &Geofence{Area: area}, nil
+}
+
+// Contains returns true if the position is within the geofence area and altitude limits.
+// A position with unknown (nil) altitude is out of the geofence if it has a floor or a ceiling.
+func (g *Geofence) Contains(lat float64, lon float64, altitude *float64) bool {
+	if g.Floor != nil || g.Ceiling != nil {
+		if altitude == nil {
+			return false
+		}
+
+		if g.Floor != nil && *altitude < *g.Floor {
+			return false
+		}
+
+		if g.Ceiling != nil && *altitude > *g.Ceiling {
+			return false
+		}
+	}
+
+	return g.Area.Contains(lat, lon)
+}
+
+// ContainsState returns true if the state vector position is within the geofence.
+// The barometric altitude is used, or the geometric altitude if it's nil.
+func (g *Geofence) ContainsState(stvec *StateVector) bool {
+	if stvec.Latitude == nil || stvec.Longitude == nil {
+		return false
+	}
+
+	altitude := stvec.BaroAltitude
+	if altitude == nil {
+		altitude = stvec.GeoAltitude
+	}
+
+	return g.Contains(*stvec.Latitude, *stvec.Longitude, altitude)
+}
+
+// Filter returns the states within the geofence.
+func (g *Geofence) Filter(states *States) *States {
+	filtered := States{Time: states.Time, States: make([]StateVector, 0), Extended: states.Extended}
+
+	for index := range states.States {
+		if g.ContainsState(&states.States[index]) {
+			filtered.States = append(filtered.States, states.States[index])
+		}
+	}
+
+	return &filtered
+}
+
+// Visits returns the entry and exit times of the flight track in the geofence.
+// The waypoints without position are ignored, the barometric altitude is used.
+func (g *Geofence) Visits(track *FlightTrack) []GeofenceVisit {
+	var visits []GeofenceVisit
+
+	inside := false
+
+	for _, waypoint := range track.Path {
+		if waypoint.Latitude == nil || waypoint.Longitude == nil {
+			continue
+		}
+
+		contained := g.Contains(*waypoint.Latitude, *waypoint.Longitude, waypoint.BaroAltitude)
+
+		switch {
+		case contained && !inside:
+			visits = append(visits, GeofenceVisit{Entry: waypoint.Time})
+		case !contained && inside:
+			visits[len(visits)-1].Exit = waypoint.Time
+		}
+
+		inside = contained
+	}
+
+	return visits
+}
+
+// BoundingBox returns the minimal bounding box enclosing the geofence area,
+// to retrieve the states of the area with GetStates before filtering them.
+func (g *Geofence) BoundingBox() *BoundingBoxOptions {
+	bounds := g.Area.Bounds()
+
+	return NewBoundingBox(bounds.MinLatitude, bounds.MinLongitude, bounds.MaxLatitude, bounds.MaxLongitude)
+}
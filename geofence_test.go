@@ -0,0 +1,125 @@
+package gopensky_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+	"github.com/navidys/gopensky/geo"
+)
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+var _ = Describe("Geofence", func() {
+	const area = "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (4 4, 6 4, 6 6, 4 6, 4 4))"
+
+	It("creates the geofences", func() {
+		geofence, err := gopensky.NewGeofenceFromWKT(area)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(geofence.Area).To(HaveLen(1))
+
+		geofence, err = gopensky.NewGeofenceFromGeoJSON([]byte(`{"type": "Feature", "geometry": {
+			"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]]]}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(geofence.Contains(5, 5, nil)).To(BeTrue())
+
+		geofence, err = gopensky.NewGeofence(geo.Polygon{{{Latitude: 0, Longitude: 0}, {Latitude: 1, Longitude: 1},
+			{Latitude: 0, Longitude: 1}}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(geofence.Area).To(HaveLen(1))
+
+		_, err = gopensky.NewGeofence()
+		Expect(err).To(MatchError(geo.ErrInvalidGeometry))
+
+		_, err = gopensky.NewGeofenceFromWKT("POINT (1 1)")
+		Expect(err).To(MatchError(geo.ErrInvalidGeometry))
+
+		_, err = gopensky.NewGeofenceFromGeoJSON([]byte(`{"type": "Point"}`))
+		Expect(err).To(MatchError(geo.ErrInvalidGeometry))
+	})
+
+	It("tests the positions and altitudes", func() {
+		geofence, err := gopensky.NewGeofenceFromWKT(area)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(geofence.Contains(1, 1, nil)).To(BeTrue())
+		Expect(geofence.Contains(5, 5, nil)).To(BeFalse())
+
+		geofence.Floor = floatPtr(1000)
+		geofence.Ceiling = floatPtr(3000)
+
+		Expect(geofence.Contains(1, 1, floatPtr(2000))).To(BeTrue())
+		Expect(geofence.Contains(1, 1, floatPtr(1000))).To(BeTrue())
+		Expect(geofence.Contains(1, 1, floatPtr(500))).To(BeFalse())
+		Expect(geofence.Contains(1, 1, floatPtr(3500))).To(BeFalse())
+		Expect(geofence.Contains(1, 1, nil)).To(BeFalse())
+		Expect(geofence.Contains(11, 1, floatPtr(2000))).To(BeFalse())
+	})
+
+	It("filters the states", func() {
+		geofence, err := gopensky.NewGeofenceFromWKT(area)
+		Expect(err).NotTo(HaveOccurred())
+
+		geofence.Ceiling = floatPtr(3000)
+
+		states := gopensky.States{Time: 1696755342, States: []gopensky.StateVector{
+			{Icao24: "inside", Latitude: floatPtr(1), Longitude: floatPtr(1), BaroAltitude: floatPtr(2000)},
+			{Icao24: "geoaltitude", Latitude: floatPtr(2), Longitude: floatPtr(2), GeoAltitude: floatPtr(2000)},
+			{Icao24: "above", Latitude: floatPtr(1), Longitude: floatPtr(1), BaroAltitude: floatPtr(4000)},
+			{Icao24: "hole", Latitude: floatPtr(5), Longitude: floatPtr(5), BaroAltitude: floatPtr(2000)},
+			{Icao24: "outside", Latitude: floatPtr(20), Longitude: floatPtr(1), BaroAltitude: floatPtr(2000)},
+			{Icao24: "noposition", BaroAltitude: floatPtr(2000)},
+		}}
+
+		filtered := geofence.Filter(&states)
+		Expect(filtered.Time).To(Equal(states.Time))
+		Expect(filtered.States).To(HaveLen(2))
+		Expect(filtered.States[0].Icao24).To(Equal("inside"))
+		Expect(filtered.States[1].Icao24).To(Equal("geoaltitude"))
+
+		Expect(geofence.Filter(&gopensky.States{}).States).To(BeEmpty())
+	})
+
+	It("returns the track entry and exit times", func() {
+		geofence, err := gopensky.NewGeofenceFromWKT(area)
+		Expect(err).NotTo(HaveOccurred())
+
+		track := gopensky.FlightTrack{Path: []gopensky.WayPoint{
+			{Time: 100, Latitude: floatPtr(-1), Longitude: floatPtr(1)},
+			{Time: 200, Latitude: floatPtr(1), Longitude: floatPtr(1)},
+			{Time: 250},
+			{Time: 300, Latitude: floatPtr(3), Longitude: floatPtr(3)},
+			{Time: 400, Latitude: floatPtr(5), Longitude: floatPtr(5)},
+			{Time: 500, Latitude: floatPtr(7), Longitude: floatPtr(7)},
+			{Time: 600, Latitude: floatPtr(11), Longitude: floatPtr(7)},
+			{Time: 700, Latitude: floatPtr(9), Longitude: floatPtr(9)},
+		}}
+
+		visits := geofence.Visits(&track)
+		Expect(visits).To(Equal([]gopensky.GeofenceVisit{
+			{Entry: 200, Exit: 400},
+			{Entry: 500, Exit: 600},
+			{Entry: 700, Exit: 0},
+		}))
+
+		Expect(visits[0].EntryTime()).To(Equal(time.Unix(200, 0)))
+		Expect(visits[0].ExitTime()).To(Equal(time.Unix(400, 0)))
+		Expect(visits[2].ExitTime().IsZero()).To(BeTrue())
+
+		Expect(geofence.Visits(&gopensky.FlightTrack{})).To(BeEmpty())
+	})
+
+	It("returns the enclosing bounding box", func() {
+		geofence, err := gopensky.NewGeofenceFromWKT(
+			"MULTIPOLYGON (((-5 40, 2 40, 2 45, -5 40)), ((5 48, 8 48, 8 51, 5 48)))")
+		Expect(err).NotTo(HaveOccurred())
+
+		bBox := geofence.BoundingBox()
+		Expect(bBox).To(Equal(gopensky.NewBoundingBox(40, -5, 51, 8)))
+		Expect(bBox.Validate()).To(Succeed())
+	})
+})
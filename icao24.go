@@ -0,0 +1,255 @@
+package gopensky
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	icao24Digits = 6
+
+	// US N-number block (N1 to N99999).
+	nNumberStart      = 0xA00001
+	nNumberEnd        = 0xADF7C7
+	nNumberCharset    = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	nNumberDigitset   = "0123456789"
+	nNumberSuffixSize = 1 + len(nNumberCharset)*(1+len(nNumberCharset))
+	nNumberBucket4    = 1 + len(nNumberCharset) + len(nNumberDigitset)
+	nNumberBucket3    = len(nNumberDigitset)*nNumberBucket4 + nNumberSuffixSize
+	nNumberBucket2    = len(nNumberDigitset)*nNumberBucket3 + nNumberSuffixSize
+	nNumberBucket1    = len(nNumberDigitset)*nNumberBucket2 + nNumberSuffixSize
+
+	registrationLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+//go:embed data/icao24_countries.csv
+var icao24CountriesCSV []byte
+
+// icao24Block is an ICAO 24-bit address block allocated to a country.
+type icao24Block struct {
+	start   ICAO24
+	end     ICAO24
+	country string
+}
+
+// strideRegistration maps a block of addresses to three letters registrations (e.g. C-FAAA).
+type strideRegistration struct {
+	start  ICAO24
+	prefix string
+}
+
+var icao24Blocks = sync.OnceValue(func() []icao24Block { //nolint:gochecknoglobals
+	blocks, err := parseICAO24Blocks(icao24CountriesCSV)
+	if err != nil {
+		panic(err)
+	}
+
+	return blocks
+})
+
+var strideRegistrations = []strideRegistration{ //nolint:gochecknoglobals
+	{start: 0xC00001, prefix: "C-F"},
+	{start: 0xC044A9, prefix: "C-G"},
+}
+
+// ICAO24 is the unique ICAO 24-bit address of a transponder.
+type ICAO24 uint32
+
+// ParseICAO24 parses a 6 digits hex ICAO 24-bit address (case-insensitive).
+func ParseICAO24(address string) (ICAO24, error) {
+	if len(address) != icao24Digits {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAircraftName, address)
+	}
+
+	value, err := strconv.ParseUint(address, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAircraftName, address)
+	}
+
+	return ICAO24(value), nil
+}
+
+// String returns the lowercase 6 digits hex representation used by the API.
+func (a ICAO24) String() string {
+	return fmt.Sprintf("%06x", uint32(a))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a ICAO24) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *ICAO24) UnmarshalText(text []byte) error {
+	address, err := ParseICAO24(string(text))
+	if err != nil {
+		return err
+	}
+
+	*a = address
+
+	return nil
+}
+
+// Country returns the country the address block is allocated to by ICAO.
+func (a ICAO24) Country() (string, bool) {
+	blocks := icao24Blocks()
+
+	index := sort.Search(len(blocks), func(i int) bool {
+		return blocks[i].end >= a
+	})
+	if index < len(blocks) && blocks[index].start <= a {
+		return blocks[index].country, true
+	}
+
+	return "", false
+}
+
+// Registration returns the aircraft registration when it can be derived from the address.
+// It supports the US N-numbers and the Canadian C-F and C-G registrations.
+func (a ICAO24) Registration() (string, bool) {
+	if a >= nNumberStart && a <= nNumberEnd {
+		return nNumber(int(a - nNumberStart)), true
+	}
+
+	letters := len(registrationLetters)
+
+	for _, stride := range strideRegistrations {
+		offset := int(a) - int(stride.start)
+		if offset < 0 || offset >= letters*letters*letters {
+			continue
+		}
+
+		return stride.prefix +
+			string(registrationLetters[offset/(letters*letters)]) +
+			string(registrationLetters[offset/letters%letters]) +
+			string(registrationLetters[offset%letters]), true
+	}
+
+	return "", false
+}
+
+// ICAO24 returns the parsed transponder address of the state vector.
+func (s *StateVector) ICAO24() (ICAO24, error) {
+	return ParseICAO24(s.Icao24)
+}
+
+// OriginCountryMatches cross-checks the origin country reported by the API against
+// the country the transponder address is allocated to.
+// It returns false only if both countries are known and they differ.
+func (s *StateVector) OriginCountryMatches() bool {
+	address, err := s.ICAO24()
+	if err != nil || s.OriginCountry == "" {
+		return true
+	}
+
+	country, found := address.Country()
+	if !found {
+		return true
+	}
+
+	return strings.EqualFold(country, s.OriginCountry)
+}
+
+// nNumber decodes the offset from the start of the US block into the N-number.
+func nNumber(offset int) string {
+	var builder strings.Builder
+
+	builder.WriteString("N")
+	builder.WriteByte(nNumberDigitset[offset/nNumberBucket1+1])
+
+	offset %= nNumberBucket1
+
+	for _, bucket := range []int{nNumberBucket2, nNumberBucket3} {
+		if offset < nNumberSuffixSize {
+			builder.WriteString(nNumberSuffix(offset))
+
+			return builder.String()
+		}
+
+		offset -= nNumberSuffixSize
+		builder.WriteByte(nNumberDigitset[offset/bucket])
+
+		offset %= bucket
+	}
+
+	if offset < nNumberSuffixSize {
+		builder.WriteString(nNumberSuffix(offset))
+
+		return builder.String()
+	}
+
+	offset -= nNumberSuffixSize
+	builder.WriteByte(nNumberDigitset[offset/nNumberBucket4])
+
+	if offset %= nNumberBucket4; offset > 0 {
+		builder.WriteByte((nNumberCharset + nNumberDigitset)[offset-1])
+	}
+
+	return builder.String()
+}
+
+// nNumberSuffix returns the zero, one or two letters suffix of a N-number.
+func nNumberSuffix(offset int) string {
+	if offset == 0 {
+		return ""
+	}
+
+	letters := len(nNumberCharset) + 1
+	suffix := string(nNumberCharset[(offset-1)/letters])
+
+	if rem := (offset - 1) % letters; rem > 0 {
+		suffix += string(nNumberCharset[rem-1])
+	}
+
+	return suffix
+}
+
+func parseICAO24Blocks(data []byte) ([]icao24Block, error) {
+	records, err := readCSVRecords(data)
+	if err != nil {
+		return nil, fmt.Errorf("icao24 allocation table: %w", err)
+	}
+
+	blocks := make([]icao24Block, 0, len(records))
+
+	for _, record := range records {
+		start, err := strconv.ParseUint(record[0], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parse icao24 block start %q: %w", record[0], err)
+		}
+
+		end, err := strconv.ParseUint(record[1], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parse icao24 block end %q: %w", record[1], err)
+		}
+
+		blocks = append(blocks, icao24Block{start: ICAO24(start), end: ICAO24(end), country: record[2]})
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].start < blocks[j].start
+	})
+
+	return blocks, nil
+}
+
+// normalizeICAO24 validates and returns the lowercase addresses sent to the API.
+func normalizeICAO24(addresses []string) ([]string, error) {
+	normalized := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		icao24, err := ParseICAO24(address)
+		if err != nil {
+			return nil, err
+		}
+
+		normalized = append(normalized, icao24.String())
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,160 @@
+package gopensky_test
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/h2non/gock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("ICAO24", func() {
+	It("parses and formats the hex addresses", func() {
+		address, err := gopensky.ParseICAO24("A835AF")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(address).To(Equal(gopensky.ICAO24(0xa835af)))
+		Expect(address.String()).To(Equal("a835af"))
+
+		address, err = gopensky.ParseICAO24("00000a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(address.String()).To(Equal("00000a"))
+
+		for _, invalid := range []string{"", "a835a", "a835af0", "g835af", "+835af", " 835af"} {
+			_, err = gopensky.ParseICAO24(invalid)
+			Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName), invalid)
+		}
+	})
+
+	It("marshals the address as text", func() {
+		var aircraft struct {
+			Icao24 gopensky.ICAO24 `json:"icao24"`
+		}
+
+		Expect(json.Unmarshal([]byte(`{"icao24": "3C6444"}`), &aircraft)).To(Succeed())
+		Expect(aircraft.Icao24).To(Equal(gopensky.ICAO24(0x3c6444)))
+
+		data, err := json.Marshal(aircraft)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"icao24":"3c6444"}`))
+
+		err = json.Unmarshal([]byte(`{"icao24": "3c64"}`), &aircraft)
+		Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+	})
+
+	It("returns the allocated country", func() {
+		for address, expected := range map[gopensky.ICAO24]string{
+			0xa835af: "United States",
+			0x3c6444: "Germany",
+			0x4b1814: "Switzerland",
+			0x406a3b: "United Kingdom",
+			0xc060b9: "Canada",
+			0x7c6b2d: "Australia",
+			0x780a3b: "China",
+			0x4b8000: "Turkey",
+		} {
+			country, found := address.Country()
+			Expect(found).To(BeTrue(), address.String())
+			Expect(country).To(Equal(expected), address.String())
+		}
+
+		_, found := gopensky.ICAO24(0xffffff).Country()
+		Expect(found).To(BeFalse())
+
+		_, found = gopensky.ICAO24(0x000001).Country()
+		Expect(found).To(BeFalse())
+	})
+
+	It("decodes the US N-numbers", func() {
+		for address, expected := range map[gopensky.ICAO24]string{
+			0xa00001: "N1",
+			0xa00002: "N1A",
+			0xa00003: "N1AA",
+			0xa0001a: "N1AZ",
+			0xa0001b: "N1B",
+			0xa0025a: "N10",
+			0xa835af: "N628TS",
+			0xadf7c7: "N99999",
+		} {
+			registration, found := address.Registration()
+			Expect(found).To(BeTrue(), address.String())
+			Expect(registration).To(Equal(expected), address.String())
+		}
+	})
+
+	It("decodes the Canadian registrations", func() {
+		for address, expected := range map[gopensky.ICAO24]string{
+			0xc00001: "C-FAAA",
+			0xc00002: "C-FAAB",
+			0xc044a8: "C-FZZZ",
+			0xc044a9: "C-GAAA",
+		} {
+			registration, found := address.Registration()
+			Expect(found).To(BeTrue(), address.String())
+			Expect(registration).To(Equal(expected), address.String())
+		}
+
+		for _, address := range []gopensky.ICAO24{0xa00000, 0xadf7c8, 0x3c6444, 0xc00000} {
+			_, found := address.Registration()
+			Expect(found).To(BeFalse(), address.String())
+		}
+	})
+
+	It("cross-checks the state vector origin country", func() {
+		stvec := gopensky.StateVector{Icao24: "a835af", OriginCountry: "United States"}
+		Expect(stvec.OriginCountryMatches()).To(BeTrue())
+
+		address, err := stvec.ICAO24()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(address).To(Equal(gopensky.ICAO24(0xa835af)))
+
+		stvec.OriginCountry = "Germany"
+		Expect(stvec.OriginCountryMatches()).To(BeFalse())
+
+		stvec.Icao24 = "ffffff"
+		Expect(stvec.OriginCountryMatches()).To(BeTrue())
+
+		stvec.Icao24 = "invalid"
+		Expect(stvec.OriginCountryMatches()).To(BeTrue())
+	})
+
+	It("validates and normalizes the requests addresses", func() {
+		conn, err := gopensky.NewConnection(context.Background(), "", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		defer gock.Off()
+
+		gclient, err := gopensky.GetClient(conn)
+		Expect(err).NotTo(HaveOccurred())
+		gock.InterceptClient(gclient)
+
+		_, err = gopensky.GetStates(conn, 0, []string{"a835af", "a835a"}, nil, false)
+		Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+
+		_, err = gopensky.GetOwnStates(conn, 0, []string{"xyz123"}, nil)
+		Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+
+		_, err = gopensky.GetTrackByAircraft(conn, "a835a", 0)
+		Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+
+		_, err = gopensky.GetFlightsByAircraft(conn, "a835af00", 1517184000, 1517270400)
+		Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+
+		for _, err := range gopensky.StreamStates(conn, 0, []string{"a835a"}, nil, false) {
+			Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+		}
+
+		gock.New(gopensky.OpenSkyAPIURL).
+			Get("/states/all").
+			MatchParam("icao24", "^ac96b8$").
+			Reply(200).
+			File("mock_data/all_states.json")
+
+		states, err := gopensky.GetStates(conn, 0, []string{"AC96B8"}, nil, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(states.States).NotTo(BeEmpty())
+		Expect(gock.IsDone()).To(BeTrue())
+	})
+})
@@ -0,0 +1,100 @@
+package gopensky
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// CallInfo describes an API call reported to the connection instrumenter.
+type CallInfo struct {
+	// API operation name (e.g. GetStates, GetFlightsByAircraft).
+	Operation string
+
+	// API endpoint of the call (e.g. /states/all).
+	Endpoint string
+
+	// Query parameters of the call.
+	Params url.Values
+}
+
+// CallResult is the result of an API call reported to the connection instrumenter.
+type CallResult struct {
+	// HTTP status code of the last response. It is 0 if no response was received.
+	StatusCode int
+
+	// Size of the response body in bytes.
+	ResponseSize int64
+
+	// Number of the returned items (state vectors, flights or waypoints).
+	ResultCount int
+
+	// Whether the response was served from the connection cache.
+	Cached bool
+
+	// Time spent on decoding the response.
+	DecodeDuration time.Duration
+
+	// Error returned by the call.
+	Err error
+}
+
+// Instrumenter observes the API calls of a connection (e.g. tracing and metrics, see otelgopensky package).
+type Instrumenter interface {
+	// StartCall is called before an API call is sent. The returned context is used for the call requests
+	// and the returned function is called with the result once the call is done.
+	StartCall(ctx context.Context, call CallInfo) (context.Context, func(CallResult))
+}
+
+// WithInstrumenter sets the instrumenter of the connection.
+func WithInstrumenter(instrumenter Instrumenter) Option {
+	return func(c *Connection) error {
+		c.instrumenter = instrumenter
+
+		return nil
+	}
+}
+
+// apiCall is an API call reported to the connection instrumenter.
+type apiCall struct {
+	info   CallInfo
+	result CallResult
+	done   func(CallResult)
+}
+
+// startCall starts an API call and returns the context to use for its requests.
+func (c *Connection) startCall(ctx context.Context, operation string, endpoint string, queryParams url.Values,
+) (context.Context, *apiCall) {
+	call := &apiCall{
+		info: CallInfo{
+			Operation: operation,
+			Endpoint:  endpoint,
+			Params:    queryParams,
+		},
+	}
+
+	if c.instrumenter != nil {
+		ctx, call.done = c.instrumenter.StartCall(ctx, call.info)
+	}
+
+	return ctx, call
+}
+
+// end reports the call result with the given items count and error to the instrumenter.
+func (call *apiCall) end(count int, err error) {
+	call.result.ResultCount = count
+	call.result.Err = err
+
+	if call.done != nil {
+		call.done(call.result)
+	}
+}
+
+// decode runs the decode function and adds its duration to the call decode time.
+func (call *apiCall) decode(decodeFunc func() error) error {
+	start := time.Now()
+	err := decodeFunc()
+	call.result.DecodeDuration += time.Since(start)
+
+	return err
+}
@@ -0,0 +1,90 @@
+package gopensky_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+type ctxKey string
+
+type testInstrumenter struct {
+	calls   []gopensky.CallInfo
+	results []gopensky.CallResult
+}
+
+func (t *testInstrumenter) StartCall(ctx context.Context, call gopensky.CallInfo,
+) (context.Context, func(gopensky.CallResult)) {
+	t.calls = append(t.calls, call)
+
+	return context.WithValue(ctx, ctxKey("span"), call.Operation), func(result gopensky.CallResult) {
+		t.results = append(t.results, result)
+	}
+}
+
+var _ = Describe("Instrumenter", func() {
+	It("reports the API calls", func() {
+		var spans []any
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/flights/all" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			data, err := os.ReadFile("mock_data/all_states.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			w.Write(data)
+		}))
+		defer server.Close()
+
+		spanRecorder := func(next gopensky.RoundTripFunc) gopensky.RoundTripFunc {
+			return func(req *gopensky.Request) (*http.Response, error) {
+				spans = append(spans, req.HTTPRequest.Context().Value(ctxKey("span")))
+
+				return next(req)
+			}
+		}
+
+		instrumenter := &testInstrumenter{}
+
+		client, err := gopensky.NewClient("", "",
+			gopensky.WithBaseURL(server.URL),
+			gopensky.WithInstrumenter(instrumenter),
+			gopensky.WithMiddleware(spanRecorder),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.GetStates(context.Background(), 0, nil, gopensky.NewBoundingBox(45, 5, 50, 10), false)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.GetFlightsByInterval(context.Background(), 1696755342, 1696758942)
+		Expect(err).To(HaveOccurred())
+
+		Expect(instrumenter.calls).To(HaveLen(2))
+		Expect(instrumenter.calls[0].Operation).To(Equal("GetStates"))
+		Expect(instrumenter.calls[0].Endpoint).To(Equal("/states/all"))
+		Expect(instrumenter.calls[0].Params.Get("lamin")).To(Equal("45.000000"))
+		Expect(instrumenter.calls[1].Operation).To(Equal("GetFlightsByInterval"))
+
+		Expect(instrumenter.results).To(HaveLen(2))
+		Expect(instrumenter.results[0].StatusCode).To(Equal(http.StatusOK))
+		Expect(instrumenter.results[0].ResultCount).To(Equal(6))
+		Expect(instrumenter.results[0].ResponseSize).To(BeNumerically(">", 0))
+		Expect(instrumenter.results[0].DecodeDuration).To(BeNumerically(">", 0))
+		Expect(instrumenter.results[0].Err).NotTo(HaveOccurred())
+
+		Expect(instrumenter.results[1].StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(instrumenter.results[1].Err).To(MatchError(gopensky.ErrServerUnavailable))
+
+		Expect(spans).To(Equal([]any{"GetStates", "GetFlightsByInterval"}))
+	})
+})
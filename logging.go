@@ -0,0 +1,94 @@
+package gopensky
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+const redactedValue = "REDACTED"
+
+// WithLogger sets the structured logger of the connection.
+// Requests are logged at debug level, retries and credit budget waits at warn level
+// and decode failures at error level. Credentials and tokens are never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Connection) error {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+
+		c.logger = logger
+
+		return nil
+	}
+}
+
+// LogValue implements slog.LogValuer and redacts the credentials.
+func (b *BasicAuth) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", "basic"), slog.String("auth", redactedValue))
+}
+
+// LogValue implements slog.LogValuer and redacts the client secret and the token.
+func (a *ClientCredentialsAuth) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("type", "client_credentials"),
+		slog.String("client_id", a.clientID),
+		slog.String("client_secret", redactedValue),
+		slog.String("token_url", a.tokenURL),
+	)
+}
+
+func (c *Connection) logRequest(ctx context.Context, req *Request, response *apiResponse, duration time.Duration,
+	err error,
+) {
+	attrs := []slog.Attr{
+		slog.String("endpoint", req.Endpoint),
+		slog.String("params", req.Params.Encode()),
+		slog.Duration("duration", duration),
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "api request failed", attrs...)
+
+		return
+	}
+
+	attrs = append(attrs,
+		slog.Int("status", response.StatusCode),
+		slog.Int("remaining_credits", c.rateLimit.get().Remaining),
+	)
+
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "api request", attrs...)
+}
+
+func (c *Connection) logRetry(ctx context.Context, endpoint string, queryParams url.Values, attempt int,
+	wait time.Duration, response *apiResponse, err error,
+) {
+	attrs := []slog.Attr{
+		slog.String("endpoint", endpoint),
+		slog.String("params", queryParams.Encode()),
+		slog.Int("attempt", attempt),
+		slog.Duration("wait", wait),
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	} else {
+		attrs = append(attrs, slog.Int("status", response.StatusCode))
+	}
+
+	c.logger.LogAttrs(ctx, slog.LevelWarn, "retrying api request", attrs...)
+}
+
+func (c *Connection) logStateVectorError(ctx context.Context, endpoint string, queryParams url.Values,
+	err *StateVectorError,
+) {
+	c.logger.LogAttrs(ctx, slog.LevelError, "decode state vector failed",
+		slog.String("endpoint", endpoint),
+		slog.String("params", queryParams.Encode()),
+		slog.Int("row", err.Row),
+		slog.Int("field", err.Index),
+		slog.Any("error", err.Err))
+}
@@ -0,0 +1,133 @@
+package gopensky_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+// failingAuth is an authenticator with a secret which always fails.
+type failingAuth struct {
+	token string
+}
+
+func (a *failingAuth) Authenticate(_ context.Context, _ *http.Request) error {
+	return errors.New("token expired")
+}
+
+var _ = Describe("Logging", func() {
+	var (
+		logs   *bytes.Buffer
+		logger *slog.Logger
+	)
+
+	BeforeEach(func() {
+		logs = &bytes.Buffer{}
+		logger = slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	})
+
+	Describe("WithLogger", func() {
+		It("logs requests and retries without credentials", func() {
+			attempts := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				attempts++
+				if attempts == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+
+					return
+				}
+
+				w.Header().Set("X-Rate-Limit-Remaining", "3996")
+				w.Write([]byte(`{"time": 1, "states": []}`))
+			}))
+			defer server.Close()
+
+			client, err := gopensky.NewClient("user", "secret-password",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithLogger(logger),
+				gopensky.WithRetryPolicy(gopensky.RetryPolicy{
+					MaxAttempts:          2,
+					InitialBackoff:       time.Millisecond,
+					RetryableStatusCodes: []int{http.StatusBadGateway},
+				}),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetStates(context.Background(), 0, []string{"c060b9"}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			output := logs.String()
+			Expect(output).To(ContainSubstring(`"msg":"api request","endpoint":"/states/all","params":"icao24=c060b9"`))
+			Expect(output).To(ContainSubstring(`"status":502`))
+			Expect(output).To(ContainSubstring(`"msg":"retrying api request"`))
+			Expect(output).To(ContainSubstring(`"status":200,"remaining_credits":3996`))
+			Expect(output).NotTo(ContainSubstring("secret-password"))
+			Expect(output).NotTo(ContainSubstring("dXNlcjpzZWNyZXQtcGFzc3dvcmQ="))
+		})
+
+		It("logs the decode failures with the row index", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				data, err := os.ReadFile("mock_data/errors/states05.json")
+				Expect(err).NotTo(HaveOccurred())
+
+				w.Write(data)
+			}))
+			defer server.Close()
+
+			client, err := gopensky.NewClient("", "", gopensky.WithBaseURL(server.URL), gopensky.WithLogger(logger))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetStates(context.Background(), 0, nil, nil, false)
+			Expect(err).To(HaveOccurred())
+
+			Expect(logs.String()).To(ContainSubstring(`"msg":"decode state vector failed"`))
+			Expect(logs.String()).To(ContainSubstring(`"row":0`))
+		})
+
+		It("logs the authentication failures with redacted secret", func() {
+			tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+			defer tokenServer.Close()
+
+			client, err := gopensky.NewClient("", "",
+				gopensky.WithLogger(logger),
+				gopensky.WithAuthenticator(
+					gopensky.NewClientCredentialsAuth("client", "client-secret", tokenServer.URL, nil)),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetStates(context.Background(), 0, nil, nil, false)
+			Expect(err).To(HaveOccurred())
+
+			Expect(logs.String()).To(ContainSubstring(`"msg":"api request authentication failed"`))
+			Expect(logs.String()).To(ContainSubstring(`"authenticator":"*gopensky.ClientCredentialsAuth"`))
+			Expect(logs.String()).NotTo(ContainSubstring("client-secret"))
+		})
+
+		It("logs only the type of the custom authenticators", func() {
+			client, err := gopensky.NewClient("", "",
+				gopensky.WithLogger(logger),
+				gopensky.WithAuthenticator(&failingAuth{token: "custom-secret"}),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetStates(context.Background(), 0, nil, nil, false)
+			Expect(err).To(HaveOccurred())
+
+			Expect(logs.String()).To(ContainSubstring(`"authenticator":"*gopensky_test.failingAuth"`))
+			Expect(logs.String()).NotTo(ContainSubstring("custom-secret"))
+		})
+	})
+})
@@ -0,0 +1,54 @@
+package gopensky
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Request is an API request passed through the connection middlewares.
+type Request struct {
+	// API endpoint of the request (e.g. /states/all).
+	Endpoint string
+
+	// Query parameters of the request. They are already encoded in the HTTP request URL.
+	Params url.Values
+
+	// The HTTP request, authenticated and ready to send.
+	HTTPRequest *http.Request
+}
+
+// RoundTripFunc sends an API request and returns its HTTP response.
+type RoundTripFunc func(req *Request) (*http.Response, error)
+
+// Middleware wraps the sending of the API requests (e.g. for logging, metrics, header injection,
+// response inspection or fault injection). It is called for every attempt of a request,
+// including the retries, and may return a response without calling next.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware adds middlewares to the connection.
+// The first middleware is the outermost one, it sees the request first and the response last.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Connection) error {
+		c.middlewares = append(c.middlewares, middlewares...)
+
+		return nil
+	}
+}
+
+// roundTrip sends the request through the middlewares chain to the connection HTTP client.
+func (c *Connection) roundTrip(req *Request) (*http.Response, error) {
+	next := func(req *Request) (*http.Response, error) {
+		return c.client.Do(req.HTTPRequest) //nolint:wrapcheck
+	}
+
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		next = c.middlewares[i](next)
+	}
+
+	response, err := next(req)
+	if err == nil && response == nil {
+		return nil, errNilResponse
+	}
+
+	return response, err
+}
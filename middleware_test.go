@@ -0,0 +1,130 @@
+package gopensky_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Middleware", func() {
+	var (
+		server  *httptest.Server
+		headers []string
+	)
+
+	BeforeEach(func() {
+		headers = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = append(headers, r.Header.Get("X-Request-Id"))
+
+			w.Write([]byte(`[]`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("WithMiddleware", func() {
+		It("calls the middlewares in order with the endpoint and params", func() {
+			var calls []string
+
+			tracer := func(name string) gopensky.Middleware {
+				return func(next gopensky.RoundTripFunc) gopensky.RoundTripFunc {
+					return func(req *gopensky.Request) (*http.Response, error) {
+						calls = append(calls, name+" "+req.Endpoint+" "+req.Params.Get("icao24"))
+
+						req.HTTPRequest.Header.Set("X-Request-Id", name)
+
+						response, err := next(req)
+
+						calls = append(calls, name+" "+response.Status)
+
+						return response, err
+					}
+				}
+			}
+
+			conn, err := gopensky.NewConnection(context.Background(), "", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithMiddleware(tracer("first"), tracer("second")),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetFlightsByAircraft(conn, "c060b9", 1696755342, 1696928142)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(calls).To(Equal([]string{
+				"first /flights/aircraft c060b9",
+				"second /flights/aircraft c060b9",
+				"second 200 OK",
+				"first 200 OK",
+			}))
+			Expect(headers).To(Equal([]string{"second"}))
+		})
+
+		It("injects faults which are retried", func() {
+			attempts := 0
+
+			faultInjector := func(next gopensky.RoundTripFunc) gopensky.RoundTripFunc {
+				return func(req *gopensky.Request) (*http.Response, error) {
+					attempts++
+					if attempts == 1 {
+						return &http.Response{
+							StatusCode: http.StatusServiceUnavailable,
+							Header:     http.Header{},
+							Body:       io.NopCloser(strings.NewReader("")),
+							Request:    req.HTTPRequest,
+						}, nil
+					}
+
+					return next(req)
+				}
+			}
+
+			client, err := gopensky.NewClient("", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithRetryPolicy(gopensky.RetryPolicy{
+					MaxAttempts:          2,
+					InitialBackoff:       time.Millisecond,
+					RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+				}),
+				gopensky.WithMiddleware(faultInjector),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetFlightsByInterval(context.Background(), 1696755342, 1696758942)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal(2))
+			Expect(headers).To(HaveLen(1))
+		})
+
+		It("returns error for nil response", func() {
+			nilResponse := func(_ gopensky.RoundTripFunc) gopensky.RoundTripFunc {
+				return func(_ *gopensky.Request) (*http.Response, error) {
+					return nil, nil
+				}
+			}
+
+			client, err := gopensky.NewClient("", "",
+				gopensky.WithBaseURL(server.URL),
+				gopensky.WithMiddleware(nilResponse),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetFlightsByInterval(context.Background(), 1696755342, 1696758942)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("middleware returned nil response"))
+			Expect(headers).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,75 @@
+package gopensky
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Connection created by NewConnection.
+type Option func(*Connection) error
+
+// WithBaseURL sets the OpenSky API base URL (e.g. an internal mirror or a local test server).
+func WithBaseURL(baseURL string) Option {
+	return func(c *Connection) error {
+		_url, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid url %s: %w", baseURL, err)
+		}
+
+		if _url.Scheme == "" || _url.Host == "" {
+			return fmt.Errorf("%w: %s", errInvalidBaseURL, baseURL)
+		}
+
+		c.uri = _url
+
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for the API requests.
+// The client is copied if WithTransport or WithTimeout is also given, it's never modified.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Connection) error {
+		if client == nil {
+			return errNilHTTPClient
+		}
+
+		c.client = client
+
+		return nil
+	}
+}
+
+// WithTransport sets the HTTP transport (round tripper) of the connection HTTP client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Connection) error {
+		c.transport = transport
+
+		return nil
+	}
+}
+
+// WithTimeout sets the time limit for each request made by the connection HTTP client.
+// A zero timeout means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Connection) error {
+		if timeout < 0 {
+			return fmt.Errorf("%w: %s", errInvalidTimeout, timeout)
+		}
+
+		c.timeout = &timeout
+
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Connection) error {
+		c.userAgent = userAgent
+
+		return nil
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Package otelgopensky provides OpenTelemetry tracing and metrics instrumentation for the gopensky API calls.
+
+It creates a span per API call and records the call latency, response size and decode time histograms.
+It is a separate module so the gopensky core module doesn't depend on OpenTelemetry.
+
+	instrumenter, err := otelgopensky.NewInstrumenter()
+	if err != nil {
+		return err
+	}
+
+	client, err := gopensky.NewClient("", "", gopensky.WithInstrumenter(instrumenter))
+*/
+package otelgopensky
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/navidys/gopensky"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ScopeName is the instrumentation scope name of the tracer and meter.
+	ScopeName = "github.com/navidys/gopensky/otelgopensky"
+
+	attrOperation   = attribute.Key("opensky.operation")
+	attrEndpoint    = attribute.Key("opensky.endpoint")
+	attrBBox        = attribute.Key("opensky.bbox")
+	attrTime        = attribute.Key("opensky.time")
+	attrBegin       = attribute.Key("opensky.begin")
+	attrEnd         = attribute.Key("opensky.end")
+	attrIcao24      = attribute.Key("opensky.icao24")
+	attrAirport     = attribute.Key("opensky.airport")
+	attrResultCount = attribute.Key("opensky.result_count")
+	attrStatusCode  = attribute.Key("http.response.status_code")
+)
+
+// Option configures the instrumenter.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider sets the tracer provider, the global one is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider, the global one is used by default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// Instrumenter implements gopensky.Instrumenter with OpenTelemetry traces and metrics.
+type Instrumenter struct {
+	tracer         trace.Tracer
+	duration       metric.Float64Histogram
+	responseSize   metric.Int64Histogram
+	decodeDuration metric.Float64Histogram
+}
+
+var _ gopensky.Instrumenter = (*Instrumenter)(nil)
+
+// NewInstrumenter returns a new OpenTelemetry instrumenter to use with gopensky.WithInstrumenter.
+func NewInstrumenter(opts ...Option) (*Instrumenter, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	duration, err := meter.Float64Histogram("opensky.client.duration",
+		metric.WithDescription("Duration of the OpenSky API calls."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("duration histogram: %w", err)
+	}
+
+	responseSize, err := meter.Int64Histogram("opensky.client.response.size",
+		metric.WithDescription("Size of the OpenSky API response bodies."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("response size histogram: %w", err)
+	}
+
+	decodeDuration, err := meter.Float64Histogram("opensky.client.decode.duration",
+		metric.WithDescription("Duration of the OpenSky API responses decoding."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("decode duration histogram: %w", err)
+	}
+
+	return &Instrumenter{
+		tracer:         cfg.tracerProvider.Tracer(ScopeName),
+		duration:       duration,
+		responseSize:   responseSize,
+		decodeDuration: decodeDuration,
+	}, nil
+}
+
+// StartCall starts a client span for the API call and records the call metrics once it is done.
+func (i *Instrumenter) StartCall(ctx context.Context, call gopensky.CallInfo,
+) (context.Context, func(gopensky.CallResult)) {
+	start := time.Now()
+
+	ctx, span := i.tracer.Start(ctx, call.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(callAttributes(call)...),
+	)
+
+	return ctx, func(result gopensky.CallResult) {
+		metricAttrs := metric.WithAttributes(
+			attrOperation.String(call.Operation),
+			attrEndpoint.String(call.Endpoint),
+			attrStatusCode.Int(result.StatusCode),
+		)
+
+		i.duration.Record(ctx, time.Since(start).Seconds(), metricAttrs)
+
+		if result.StatusCode != 0 {
+			i.responseSize.Record(ctx, result.ResponseSize, metricAttrs)
+		}
+
+		if result.DecodeDuration > 0 {
+			i.decodeDuration.Record(ctx, result.DecodeDuration.Seconds(), metricAttrs)
+		}
+
+		span.SetAttributes(attrResultCount.Int(result.ResultCount))
+
+		if result.StatusCode != 0 {
+			span.SetAttributes(attrStatusCode.Int(result.StatusCode))
+		}
+
+		if result.Err != nil {
+			span.RecordError(result.Err)
+			span.SetStatus(codes.Error, result.Err.Error())
+		} else if result.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, http.StatusText(result.StatusCode))
+		}
+
+		span.End()
+	}
+}
+
+// callAttributes returns the span attributes of the API call parameters.
+func callAttributes(call gopensky.CallInfo) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attrOperation.String(call.Operation),
+		attrEndpoint.String(call.Endpoint),
+	}
+
+	params := call.Params
+
+	if params.Has("lamin") {
+		bBox := strings.Join([]string{
+			params.Get("lamin"), params.Get("lomin"), params.Get("lamax"), params.Get("lomax"),
+		}, ",")
+		attrs = append(attrs, attrBBox.String(bBox))
+	}
+
+	for _, param := range []struct {
+		key  attribute.Key
+		name string
+	}{
+		{key: attrTime, name: "time"},
+		{key: attrBegin, name: "begin"},
+		{key: attrEnd, name: "end"},
+		{key: attrAirport, name: "airport"},
+	} {
+		if params.Has(param.name) {
+			attrs = append(attrs, param.key.String(params.Get(param.name)))
+		}
+	}
+
+	if icao24 := params["icao24"]; len(icao24) > 0 {
+		attrs = append(attrs, attrIcao24.StringSlice(icao24))
+	}
+
+	return attrs
+}
@@ -0,0 +1,13 @@
+package otelgopensky_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOtelgopensky(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Otelgopensky Suite")
+}
@@ -0,0 +1,106 @@
+package otelgopensky_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/navidys/gopensky"
+	"github.com/navidys/gopensky/otelgopensky"
+)
+
+var _ = Describe("Instrumenter", func() {
+	It("creates a span and records metrics per API call", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/flights/aircraft" {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			data, err := os.ReadFile("../mock_data/all_states.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			w.Write(data)
+		}))
+		defer server.Close()
+
+		spanRecorder := tracetest.NewSpanRecorder()
+		metricReader := sdkmetric.NewManualReader()
+
+		instrumenter, err := otelgopensky.NewInstrumenter(
+			otelgopensky.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))),
+			otelgopensky.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricReader))),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		client, err := gopensky.NewClient("", "",
+			gopensky.WithBaseURL(server.URL),
+			gopensky.WithInstrumenter(instrumenter),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.GetStates(context.Background(), 1696755342, nil, gopensky.NewBoundingBox(45, 5, 50, 10), false)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.GetFlightsByAircraft(context.Background(), "c060b9", 1696755342, 1696758942)
+		Expect(err).To(HaveOccurred())
+
+		spans := spanRecorder.Ended()
+		Expect(spans).To(HaveLen(2))
+
+		Expect(spans[0].Name()).To(Equal("GetStates"))
+		Expect(spans[0].Attributes()).To(ContainElements(
+			attribute.String("opensky.endpoint", "/states/all"),
+			attribute.String("opensky.bbox", "45.000000,5.000000,50.000000,10.000000"),
+			attribute.String("opensky.time", "1696755342"),
+			attribute.Int("opensky.result_count", 6),
+			attribute.Int("http.response.status_code", http.StatusOK),
+		))
+		Expect(spans[0].Status().Code).To(Equal(codes.Unset))
+
+		Expect(spans[1].Name()).To(Equal("GetFlightsByAircraft"))
+		Expect(spans[1].Attributes()).To(ContainElements(
+			attribute.StringSlice("opensky.icao24", []string{"c060b9"}),
+			attribute.String("opensky.begin", "1696755342"),
+			attribute.String("opensky.end", "1696758942"),
+			attribute.Int("http.response.status_code", http.StatusNotFound),
+		))
+		Expect(spans[1].Status().Code).To(Equal(codes.Error))
+
+		var metrics metricdata.ResourceMetrics
+		Expect(metricReader.Collect(context.Background(), &metrics)).To(Succeed())
+		Expect(metrics.ScopeMetrics).To(HaveLen(1))
+
+		histograms := map[string]int{}
+
+		for _, m := range metrics.ScopeMetrics[0].Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, point := range data.DataPoints {
+					histograms[m.Name] += int(point.Count)
+				}
+			case metricdata.Histogram[int64]:
+				for _, point := range data.DataPoints {
+					histograms[m.Name] += int(point.Count)
+				}
+			}
+		}
+
+		Expect(histograms).To(Equal(map[string]int{
+			"opensky.client.duration":        2,
+			"opensky.client.response.size":   2,
+			"opensky.client.decode.duration": 2,
+		}))
+	})
+})
@@ -0,0 +1,193 @@
+package gopensky
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts    = 4
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 30 * time.Second
+	defaultRetryMultiplier     = 2
+	defaultRetryJitter         = 0.2
+
+	rateLimitRetryAfterHeader = "X-Rate-Limit-Retry-After-Seconds"
+	retryAfterHeader          = "Retry-After"
+)
+
+// RetryPolicy controls how failed API requests are retried.
+// Transport errors and the retryable status codes are retried,
+// the context cancellation stops waiting between the attempts.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one. Values <= 1 disable the retries.
+	MaxAttempts int
+
+	// Wait time before the first retry.
+	InitialBackoff time.Duration
+
+	// Upper limit of the wait time between the attempts.
+	// The request is not retried if the server asks (Retry-After) to wait longer.
+	MaxBackoff time.Duration
+
+	// Factor by which the wait time grows after each attempt.
+	Multiplier float64
+
+	// Randomization factor [0, 1] of the wait time (e.g. 0.2 = +/- 20%).
+	Jitter float64
+
+	// HTTP status codes which are retried.
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns a retry policy with 4 attempts, exponential backoff from 500ms to 30s,
+// which retries rate limited (429) and server unavailable (500, 502, 503, 504) responses.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		Multiplier:     defaultRetryMultiplier,
+		Jitter:         defaultRetryJitter,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the connection. Requests are not retried by default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Connection) error {
+		c.retryPolicy = &policy
+
+		return nil
+	}
+}
+
+// backoff returns the wait time before the given retry (1 = first retry).
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		wait *= 1 + jitter*(2*rand.Float64()-1) //nolint:gosec,mnd
+	}
+
+	// the jitter is applied first, so the wait time never exceeds the max backoff.
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+
+	return time.Duration(wait)
+}
+
+func (p *RetryPolicy) isRetryableStatus(statusCode int) bool {
+	return slices.Contains(p.RetryableStatusCodes, statusCode)
+}
+
+// retryAfter returns the server requested wait time from the
+// X-Rate-Limit-Retry-After-Seconds or Retry-After (seconds or HTTP date) headers.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := header.Get(rateLimitRetryAfterHeader); value != "" {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	value := header.Get(retryAfterHeader)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
+
+// doGetRequest sends the request and retries it according to the connection retry policy.
+// Note: Closing the response.Body is left to the caller.
+func (c *Connection) doGetRequest(ctx context.Context, endpoint string, queryParams url.Values,
+) (*apiResponse, error) {
+	cost := requestCost(endpoint, queryParams)
+	policy := c.retryPolicy
+
+	for attempt := 1; ; attempt++ {
+		// each attempt is charged by the API.
+		if err := c.reserveCredits(ctx, cost); err != nil {
+			return nil, err
+		}
+
+		response, err := c.doAuthenticatedRequest(ctx, endpoint, queryParams)
+		if policy == nil || attempt >= policy.MaxAttempts {
+			return response, err
+		}
+
+		var wait time.Duration
+
+		switch {
+		case err != nil:
+			// only transport errors are retried (the request has been sent).
+			if response == nil || ctx.Err() != nil {
+				return response, err
+			}
+
+			wait = policy.backoff(attempt)
+		case policy.isRetryableStatus(response.StatusCode):
+			wait = policy.backoff(attempt)
+
+			if serverWait, ok := retryAfter(response.Header, time.Now()); ok {
+				if policy.MaxBackoff > 0 && serverWait > policy.MaxBackoff {
+					return response, nil
+				}
+
+				wait = serverWait
+			}
+
+			_, _ = io.Copy(io.Discard, response.Body)
+			_ = response.Body.Close()
+		default:
+			return response, nil
+		}
+
+		c.logRetry(ctx, endpoint, queryParams, attempt, wait, response, err)
+
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package gopensky_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Retry", func() {
+	Describe("doGetRequest", func() {
+		var (
+			requests atomic.Int32
+			waits    []time.Duration
+		)
+
+		newConnection := func(serverURL string, policy gopensky.RetryPolicy) context.Context {
+			conn, err := gopensky.NewConnection(context.Background(), "", "",
+				gopensky.WithBaseURL(serverURL),
+				gopensky.WithRetryPolicy(policy),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			waits = nil
+			gopensky.SetConnectionSleep(conn, func(ctx context.Context, wait time.Duration) error {
+				waits = append(waits, wait)
+
+				return ctx.Err()
+			})
+
+			return conn
+		}
+
+		BeforeEach(func() {
+			requests.Store(0)
+		})
+
+		It("retries the retryable status codes with backoff", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if requests.Add(1) < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			policy := gopensky.DefaultRetryPolicy()
+			policy.Jitter = 0
+
+			conn := newConnection(server.URL, policy)
+
+			_, err := gopensky.GetFlightsByInterval(conn, 1696755342, 1696758942)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests.Load()).To(Equal(int32(3)))
+			Expect(waits).To(Equal([]time.Duration{500 * time.Millisecond, time.Second}))
+		})
+
+		It("honors the retry after headers", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				switch requests.Add(1) {
+				case 1:
+					w.Header().Set("X-Rate-Limit-Retry-After-Seconds", "7")
+					w.WriteHeader(http.StatusTooManyRequests)
+				case 2:
+					w.Header().Set("Retry-After", "3")
+					w.WriteHeader(http.StatusTooManyRequests)
+				default:
+					w.Write([]byte(`[]`))
+				}
+			}))
+			defer server.Close()
+
+			conn := newConnection(server.URL, gopensky.DefaultRetryPolicy())
+
+			_, err := gopensky.GetFlightsByInterval(conn, 1696755342, 1696758942)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waits).To(Equal([]time.Duration{7 * time.Second, 3 * time.Second}))
+		})
+
+		It("gives up after max attempts or too long retry after", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+
+				if r.URL.Path == "/flights/all" {
+					w.Header().Set("X-Rate-Limit-Retry-After-Seconds", "3600")
+					w.WriteHeader(http.StatusTooManyRequests)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+			defer server.Close()
+
+			conn := newConnection(server.URL, gopensky.DefaultRetryPolicy())
+
+			_, err := gopensky.GetFlightsByInterval(conn, 1696755342, 1696758942)
+			Expect(err).To(HaveOccurred())
+			Expect(requests.Load()).To(Equal(int32(1)))
+			Expect(waits).To(BeEmpty())
+
+			_, err = gopensky.GetDeparturesByAirport(conn, "KEWR", 1696755342, 1696758942)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(http.StatusText(http.StatusBadGateway)))
+			Expect(requests.Load()).To(Equal(int32(5)))
+			Expect(waits).To(HaveLen(3))
+		})
+
+		It("does not retry the non retryable status codes", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer server.Close()
+
+			conn := newConnection(server.URL, gopensky.DefaultRetryPolicy())
+
+			_, err := gopensky.GetTrackByAircraft(conn, "c060b9", 0)
+			Expect(err).To(HaveOccurred())
+			Expect(requests.Load()).To(Equal(int32(1)))
+		})
+
+		It("retries transport errors and stops on context cancellation", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+			serverURL := server.URL
+			server.Close()
+
+			conn := newConnection(serverURL, gopensky.DefaultRetryPolicy())
+
+			_, err := gopensky.GetFlightsByInterval(conn, 1696755342, 1696758942)
+			Expect(err).To(HaveOccurred())
+			Expect(waits).To(HaveLen(3))
+
+			ctx, cancel := context.WithCancel(conn)
+			gopensky.SetConnectionSleep(conn, func(_ context.Context, wait time.Duration) error {
+				waits = append(waits, wait)
+				cancel()
+
+				return context.Canceled
+			})
+
+			waits = nil
+			_, err = gopensky.GetFlightsByInterval(ctx, 1696755342, 1696758942)
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(waits).To(HaveLen(1))
+		})
+	})
+
+	Describe("backoff", func() {
+		It("grows exponentially up to max backoff with jitter", func() {
+			policy := gopensky.DefaultRetryPolicy()
+			policy.Jitter = 0
+
+			Expect(gopensky.RetryPolicyBackoff(policy, 1)).To(Equal(500 * time.Millisecond))
+			Expect(gopensky.RetryPolicyBackoff(policy, 3)).To(Equal(2 * time.Second))
+			Expect(gopensky.RetryPolicyBackoff(policy, 10)).To(Equal(30 * time.Second))
+
+			policy.Jitter = 0.5
+			for range 10 {
+				Expect(gopensky.RetryPolicyBackoff(policy, 2)).To(And(
+					BeNumerically(">=", 500*time.Millisecond),
+					BeNumerically("<=", 1500*time.Millisecond),
+				))
+			}
+
+			for range 10 {
+				Expect(gopensky.RetryPolicyBackoff(policy, 10)).To(And(
+					BeNumerically(">=", 15*time.Second),
+					BeNumerically("<=", 30*time.Second),
+				))
+			}
+		})
+	})
+
+	Describe("retryAfter", func() {
+		It("parses the retry after headers", func() {
+			now := time.Date(2023, 10, 8, 10, 0, 0, 0, time.UTC)
+
+			header := http.Header{}
+			_, ok := gopensky.RetryAfter(header, now)
+			Expect(ok).To(BeFalse())
+
+			header.Set("Retry-After", now.Add(time.Minute).Format(http.TimeFormat))
+			wait, ok := gopensky.RetryAfter(header, now)
+			Expect(ok).To(BeTrue())
+			Expect(wait).To(Equal(time.Minute))
+
+			header.Set("Retry-After", "invalid")
+			_, ok = gopensky.RetryAfter(header, now)
+			Expect(ok).To(BeFalse())
+
+			header.Set("X-Rate-Limit-Retry-After-Seconds", "12")
+			wait, ok = gopensky.RetryAfter(header, now)
+			Expect(ok).To(BeTrue())
+			Expect(wait).To(Equal(12 * time.Second))
+		})
+	})
+})
@@ -0,0 +1,210 @@
+package gopensky
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+const squawkDigits = 4
+
+// Squawk is a 4 octal digits transponder (Mode A) code.
+type Squawk uint16
+
+const (
+	SquawkCodeNotAssigned  Squawk = 0o2000 // No code assigned (entering a SSR area)
+	SquawkCodeVFR          Squawk = 0o7000 // VFR conspicuity (ICAO)
+	SquawkCodeVFRUS        Squawk = 0o1200 // VFR conspicuity (United States)
+	SquawkCodeHijack       Squawk = 0o7500 // Unlawful interference
+	SquawkCodeRadioFailure Squawk = 0o7600 // Radio communication failure
+	SquawkCodeEmergency    Squawk = 0o7700 // General emergency
+)
+
+// SquawkClass is the classification of a squawk code.
+type SquawkClass int
+
+const (
+	SquawkDiscrete     SquawkClass = iota // Discrete code assigned by the air traffic control
+	SquawkHijack                          // 7500 unlawful interference
+	SquawkRadioFailure                    // 7600 radio communication failure
+	SquawkEmergency                       // 7700 general emergency
+	SquawkVFR                             // VFR conspicuity code (e.g. 7000, 1200)
+	SquawkNotAssigned                     // 2000 no code assigned
+	SquawkMilitary                        // Military code
+	SquawkReserved                        // Other reserved or special purpose code
+)
+
+var squawkClassNames = [...]string{ //nolint:gochecknoglobals
+	SquawkDiscrete:     "discrete",
+	SquawkHijack:       "hijack",
+	SquawkRadioFailure: "radio-failure",
+	SquawkEmergency:    "emergency",
+	SquawkVFR:          "vfr",
+	SquawkNotAssigned:  "not-assigned",
+	SquawkMilitary:     "military",
+	SquawkReserved:     "reserved",
+}
+
+// SquawkRange is a range of squawk codes [First, Last] of a code allocation table.
+type SquawkRange struct {
+	First       Squawk
+	Last        Squawk
+	Class       SquawkClass
+	Description string
+}
+
+// SquawkTable is a squawk code allocation table, the first range containing a code is used.
+type SquawkTable []SquawkRange
+
+// ParseSquawk parses a 4 octal digits squawk code (e.g. 7700).
+func ParseSquawk(code string) (Squawk, error) {
+	if len(code) != squawkDigits {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSquawk, code)
+	}
+
+	value, err := strconv.ParseUint(code, 8, 16)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSquawk, code)
+	}
+
+	return Squawk(value), nil
+}
+
+// String returns the 4 octal digits squawk code.
+func (s Squawk) String() string {
+	return fmt.Sprintf("%04o", uint16(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Squawk) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Squawk) UnmarshalText(text []byte) error {
+	squawk, err := ParseSquawk(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = squawk
+
+	return nil
+}
+
+// Class returns the squawk classification of the ICAO code allocation table (see ICAOSquawkTable).
+// Use the national tables (e.g. USSquawkTable().Class) for the other national codes.
+func (s Squawk) Class() SquawkClass {
+	return icaoSquawkTable.Class(s)
+}
+
+// IsEmergency returns true for the 7500, 7600 and 7700 codes.
+func (s Squawk) IsEmergency() bool {
+	return s.Class().IsEmergency()
+}
+
+// String returns the squawk class name (e.g. emergency, vfr).
+func (c SquawkClass) String() string {
+	if c >= 0 && int(c) < len(squawkClassNames) {
+		return squawkClassNames[c]
+	}
+
+	return "SquawkClass(" + strconv.Itoa(int(c)) + ")"
+}
+
+// IsEmergency returns true for the hijack, radio failure and emergency classes.
+func (c SquawkClass) IsEmergency() bool {
+	return c == SquawkHijack || c == SquawkRadioFailure || c == SquawkEmergency
+}
+
+// Lookup returns the first range of the table containing the code.
+func (t SquawkTable) Lookup(squawk Squawk) (SquawkRange, bool) {
+	for _, codeRange := range t {
+		if squawk >= codeRange.First && squawk <= codeRange.Last {
+			return codeRange, true
+		}
+	}
+
+	return SquawkRange{}, false
+}
+
+// Class returns the class of the code, the codes which are not in the table are discrete codes.
+func (t SquawkTable) Class(squawk Squawk) SquawkClass {
+	codeRange, found := t.Lookup(squawk)
+	if !found {
+		return SquawkDiscrete
+	}
+
+	return codeRange.Class
+}
+
+// icaoSquawkTable is the default code allocation table.
+var icaoSquawkTable = SquawkTable{ //nolint:gochecknoglobals
+	{First: SquawkCodeHijack, Last: SquawkCodeHijack, Class: SquawkHijack, Description: "Unlawful interference"},
+	{
+		First: SquawkCodeRadioFailure, Last: SquawkCodeRadioFailure, Class: SquawkRadioFailure,
+		Description: "Radio communication failure",
+	},
+	{First: SquawkCodeEmergency, Last: SquawkCodeEmergency, Class: SquawkEmergency, Description: "General emergency"},
+	{First: SquawkCodeVFR, Last: SquawkCodeVFR, Class: SquawkVFR, Description: "VFR conspicuity"},
+	{
+		First: SquawkCodeNotAssigned, Last: SquawkCodeNotAssigned, Class: SquawkNotAssigned,
+		Description: "No code assigned",
+	},
+	{First: 0o1000, Last: 0o1000, Class: SquawkReserved, Description: "Mode S conspicuity (IFR)"},
+	{First: 0o7400, Last: 0o7400, Class: SquawkReserved, Description: "Unmanned aircraft lost link"},
+	{First: 0o0000, Last: 0o0000, Class: SquawkReserved, Description: "Not to be used"},
+	{First: 0o7777, Last: 0o7777, Class: SquawkMilitary, Description: "Military interceptor and transponder test"},
+	{First: SquawkCodeVFRUS, Last: SquawkCodeVFRUS, Class: SquawkVFR, Description: "VFR conspicuity (North America)"},
+}
+
+// ICAOSquawkTable returns the ICAO code allocation table, the default table of Squawk.Class.
+// It includes the North American 1200 VFR conspicuity code.
+func ICAOSquawkTable() SquawkTable {
+	return slices.Clone(icaoSquawkTable)
+}
+
+// USSquawkTable returns the United States (FAA) code allocation table, including the ICAO codes.
+func USSquawkTable() SquawkTable {
+	return append(SquawkTable{
+		{First: 0o1255, Last: 0o1255, Class: SquawkReserved, Description: "Fire fighting aircraft"},
+		{First: 0o1277, Last: 0o1277, Class: SquawkReserved, Description: "Search and rescue"},
+		{First: 0o4400, Last: 0o4477, Class: SquawkMilitary, Description: "Military and high altitude operations"},
+	}, icaoSquawkTable...)
+}
+
+// UKSquawkTable returns the United Kingdom code allocation table, including the ICAO codes.
+func UKSquawkTable() SquawkTable {
+	return append(SquawkTable{
+		{First: 0o7001, Last: 0o7001, Class: SquawkMilitary, Description: "Military low level conspicuity"},
+		{First: 0o0033, Last: 0o0033, Class: SquawkReserved, Description: "Parachute drop"},
+		{First: SquawkCodeVFRUS, Last: SquawkCodeVFRUS, Class: SquawkDiscrete, Description: "Discrete code"},
+	}, icaoSquawkTable...)
+}
+
+// SquawkCode returns the parsed squawk of the state vector, false if it's nil or invalid.
+func (s *StateVector) SquawkCode() (Squawk, bool) {
+	if s.Squawk == nil {
+		return 0, false
+	}
+
+	squawk, err := ParseSquawk(*s.Squawk)
+	if err != nil {
+		return 0, false
+	}
+
+	return squawk, true
+}
+
+// Emergencies returns the state vectors squawking an emergency code (7500, 7600 or 7700).
+func (s *States) Emergencies() []StateVector {
+	emergencies := make([]StateVector, 0)
+
+	for _, stvec := range s.States {
+		if squawk, ok := stvec.SquawkCode(); ok && squawk.IsEmergency() {
+			emergencies = append(emergencies, stvec)
+		}
+	}
+
+	return emergencies
+}
@@ -0,0 +1,119 @@
+package gopensky_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Squawk", func() {
+	It("parses and formats the octal codes", func() {
+		squawk, err := gopensky.ParseSquawk("7700")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(squawk).To(Equal(gopensky.SquawkCodeEmergency))
+		Expect(squawk.String()).To(Equal("7700"))
+
+		squawk, err = gopensky.ParseSquawk("0033")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(squawk).To(Equal(gopensky.Squawk(0o33)))
+		Expect(squawk.String()).To(Equal("0033"))
+
+		for _, invalid := range []string{"", "770", "77000", "7800", "7709", "+777", "abcd"} {
+			_, err = gopensky.ParseSquawk(invalid)
+			Expect(err).To(MatchError(gopensky.ErrInvalidSquawk), invalid)
+		}
+	})
+
+	It("marshals the code as text", func() {
+		var stvec struct {
+			Squawk gopensky.Squawk `json:"squawk"`
+		}
+
+		Expect(json.Unmarshal([]byte(`{"squawk": "1200"}`), &stvec)).To(Succeed())
+		Expect(stvec.Squawk).To(Equal(gopensky.SquawkCodeVFRUS))
+
+		data, err := json.Marshal(stvec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"squawk":"1200"}`))
+
+		Expect(json.Unmarshal([]byte(`{"squawk": "1289"}`), &stvec)).To(MatchError(gopensky.ErrInvalidSquawk))
+	})
+
+	It("classifies the codes", func() {
+		for code, expected := range map[string]gopensky.SquawkClass{
+			"7500": gopensky.SquawkHijack,
+			"7600": gopensky.SquawkRadioFailure,
+			"7700": gopensky.SquawkEmergency,
+			"7000": gopensky.SquawkVFR,
+			"2000": gopensky.SquawkNotAssigned,
+			"7777": gopensky.SquawkMilitary,
+			"1000": gopensky.SquawkReserved,
+			"1200": gopensky.SquawkVFR,
+			"4452": gopensky.SquawkDiscrete,
+			"3421": gopensky.SquawkDiscrete,
+		} {
+			squawk, err := gopensky.ParseSquawk(code)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(squawk.Class()).To(Equal(expected), code)
+			Expect(squawk.IsEmergency()).To(Equal(expected.IsEmergency()), code)
+		}
+
+		icaoTable := gopensky.ICAOSquawkTable()
+		Expect(icaoTable.Class(gopensky.SquawkCodeVFRUS)).To(Equal(gopensky.SquawkCodeVFRUS.Class()))
+
+		icaoTable[0].Class = gopensky.SquawkDiscrete
+		Expect(gopensky.SquawkCodeHijack.Class()).To(Equal(gopensky.SquawkHijack))
+
+		Expect(gopensky.SquawkEmergency.String()).To(Equal("emergency"))
+		Expect(gopensky.SquawkClass(42).String()).To(Equal("SquawkClass(42)"))
+	})
+
+	It("classifies the codes with the national tables", func() {
+		usTable := gopensky.USSquawkTable()
+		Expect(usTable.Class(gopensky.SquawkCodeVFRUS)).To(Equal(gopensky.SquawkVFR))
+		Expect(usTable.Class(0o4452)).To(Equal(gopensky.SquawkMilitary))
+		Expect(usTable.Class(gopensky.SquawkCodeEmergency)).To(Equal(gopensky.SquawkEmergency))
+
+		codeRange, found := usTable.Lookup(0o1277)
+		Expect(found).To(BeTrue())
+		Expect(codeRange.Description).To(Equal("Search and rescue"))
+
+		ukTable := gopensky.UKSquawkTable()
+		Expect(ukTable.Class(0o7001)).To(Equal(gopensky.SquawkMilitary))
+		Expect(ukTable.Class(gopensky.SquawkCodeVFR)).To(Equal(gopensky.SquawkVFR))
+		Expect(ukTable.Class(gopensky.SquawkCodeVFRUS)).To(Equal(gopensky.SquawkDiscrete))
+
+		_, found = ukTable.Lookup(0o3421)
+		Expect(found).To(BeFalse())
+	})
+
+	It("filters the emergency state vectors", func() {
+		squawks := []string{"7700", "1200", "7500", "77", "7600", "3421"}
+
+		states := gopensky.States{States: []gopensky.StateVector{{Icao24: "nosquawk"}}}
+		for index := range squawks {
+			states.States = append(states.States, gopensky.StateVector{Icao24: squawks[index], Squawk: &squawks[index]})
+		}
+
+		emergencies := states.Emergencies()
+		Expect(emergencies).To(HaveLen(3))
+		Expect(emergencies[0].Icao24).To(Equal("7700"))
+		Expect(emergencies[1].Icao24).To(Equal("7500"))
+		Expect(emergencies[2].Icao24).To(Equal("7600"))
+
+		_, found := states.States[0].SquawkCode()
+		Expect(found).To(BeFalse())
+
+		_, found = states.States[4].SquawkCode()
+		Expect(found).To(BeFalse())
+
+		squawk, found := states.States[1].SquawkCode()
+		Expect(found).To(BeTrue())
+		Expect(squawk).To(Equal(gopensky.SquawkCodeEmergency))
+
+		Expect((&gopensky.States{}).Emergencies()).To(BeEmpty())
+	})
+})
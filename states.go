@@ -2,8 +2,10 @@ package gopensky
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 )
 
 const (
@@ -27,6 +29,8 @@ const (
 	stateVecCategoryIndex
 )
 
+// StatesResponse is the raw states API response.
+// The API functions decode the state vectors rows with a typed decoder, it's kept for compatibility.
 type StatesResponse struct {
 	// The time which the state vectors in this response are associated with.
 	// All vectors represent the state of a vehicle with the interval.
@@ -44,6 +48,10 @@ type States struct {
 	// The state vectors.
 	// States []StateVector `json:"states"`
 	States []StateVector `json:"states"`
+
+	// Whether the state vectors have the aircraft category (extended request).
+	// The Response method only encodes the category field of the extended states.
+	Extended bool `json:"extended,omitempty"`
 }
 
 type StateVector struct {
@@ -100,36 +108,12 @@ type StateVector struct {
 	// Whether flight status indicates special purpose indicator.
 	Spi bool `json:"spi"`
 
-	// Origin of this state’s position.
-	// 0 = ADS-B
-	// 1 = ASTERIX
-	// 2 = MLAT
-	// 3 = FLARM
-	PositionSource int `json:"positionSource"`
-
-	// Aircraft category.
-	// 0 = No information at all
-	// 1 = No ADS-B Emitter Category Information
-	// 2 = Light (< 15500 lbs)
-	// 3 = Small (15500 to 75000 lbs)
-	// 4 = Large (75000 to 300000 lbs)
-	// 5 = High Vortex Large (aircraft such as B-757)
-	// 6 = Heavy (> 300000 lbs)
-	// 7 = High Performance (> 5g acceleration and 400 kts)
-	// 8 = Rotorcraft
-	// 9 = Glider / sailplane
-	// 10 = Lighter-than-air
-	// 11 = Parachutist / Skydiver
-	// 12 = Ultralight / hang-glider / paraglider
-	// 13 = Reserved
-	// 14 = Unmanned Aerial Vehicle
-	// 15 = Space / Trans-atmospheric vehicle
-	// 16 = Surface Vehicle – Emergency Vehicle
-	// 17 = Surface Vehicle – Service Vehicle
-	// 18 = Point Obstacle (includes tethered balloons)
-	// 19 = Cluster Obstacle
-	// 20 = Line Obstacle
-	Category int `json:"category"`
+	// Origin of this state’s position (ADS-B, ASTERIX, MLAT or FLARM).
+	PositionSource PositionSource `json:"positionSource"`
+
+	// Aircraft category (e.g. CategoryHeavy, CategoryRotorcraft).
+	// It's only set if the extended state vectors were requested.
+	Category AircraftCategory `json:"category"`
 }
 
 type BoundingBoxOptions struct {
@@ -147,256 +131,137 @@ type BoundingBoxOptions struct {
 }
 
 // GetStates retrieve state vectors for a given time. If time = 0 the most recent ones are taken.
-// It is possible to query a certain area defined by a bounding box of WGS84 coordinates.
+// It is possible to query a certain area defined by a bounding box of WGS84 coordinates,
+// the boxes crossing the antimeridian are split in two requests (see GetStatesInRegions).
 // You can request the category of aircraft by setting extended to true.
 func GetStates(ctx context.Context, time int64, icao24 []string,
 	bBox *BoundingBoxOptions, extended bool,
 ) (*States, error) {
-	var statesRep StatesResponse
-
-	conn, err := getClient(ctx)
+	client, err := contextClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("client: %w", err)
+		return nil, err
 	}
 
+	return client.GetStates(ctx, time, icao24, bBox, extended)
+}
+
+// GetStates retrieve state vectors for a given time. If time = 0 the most recent ones are taken.
+// It is possible to query a certain area defined by a bounding box of WGS84 coordinates,
+// the boxes crossing the antimeridian are split in two requests (see GetStatesInRegions).
+// You can request the category of aircraft by setting extended to true.
+func (c *Client) GetStates(ctx context.Context, time int64, icao24 []string,
+	bBox *BoundingBoxOptions, extended bool,
+) (*States, error) {
 	if time < 0 {
 		return nil, ErrInvalidUnixTime
 	}
 
-	requestParams := getStateRequestParams(time, icao24, bBox, extended)
-
-	response, err := conn.doGetRequest(ctx, "/states/all", requestParams)
-	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
-
-	errRespProcess := response.process(&statesRep)
-
-	err = response.Body.Close()
+	icao24, err := normalizeICAO24(icao24)
 	if err != nil {
-		return nil, fmt.Errorf("response body close %w", err)
-	}
-
-	if errRespProcess != nil {
-		return nil, errRespProcess
+		return nil, err
 	}
 
-	statesVecList := make([]StateVector, 0)
-
-	for _, st := range statesRep.States {
-		stvec, err := decodeRawStateVector(st)
-		if err != nil {
-			return nil, fmt.Errorf("decode state vector: %w", err)
+	if bBox != nil {
+		if err := bBox.Validate(); err != nil {
+			return nil, err
 		}
 
-		if stvec != nil {
-			statesVecList = append(statesVecList, *stvec)
+		if bBox.CrossesAntimeridian() {
+			return c.GetStatesInRegions(ctx, time, icao24, []BoundingBoxOptions{*bBox}, extended)
 		}
 	}
 
-	states := States{
-		Time:   statesRep.Time,
-		States: statesVecList,
-	}
+	requestParams := getStateRequestParams(time, icao24, bBox, extended)
 
-	return &states, nil
+	return c.getStates(ctx, "GetStates", "/states/all", requestParams)
 }
 
-func getStateRequestParams(time int64, icao24 []string, bBox *BoundingBoxOptions, extended bool) url.Values {
-	requestParams := make(url.Values)
-	if time > 0 {
-		requestParams.Add("time", fmt.Sprintf("%d", time)) //nolint:perfsprint
-	}
-
-	for _, icao24Item := range icao24 {
-		requestParams.Add("icao24", icao24Item)
-	}
-
-	if extended {
-		requestParams.Add("extended", "1")
-	}
-
-	if bBox != nil {
-		requestParams.Add("lamax", floatToString(bBox.Lamax))
-		requestParams.Add("lamin", floatToString(bBox.Lamin))
-		requestParams.Add("lomax", floatToString(bBox.Lomax))
-		requestParams.Add("lomin", floatToString(bBox.Lomin))
+// GetOwnStates retrieves the state vectors seen by the authenticated user's own sensors for a given time.
+// If time = 0 the most recent ones are taken. The state vectors can be filtered by ICAO24 addresses
+// and by the receivers serial numbers. The Sensors field holds the IDs of the receivers which contributed.
+func GetOwnStates(ctx context.Context, time int64, icao24 []string, serials []int) (*States, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return requestParams
+	return client.GetOwnStates(ctx, time, icao24, serials)
 }
 
-func decodeRawStateVector(data []any) (*StateVector, error) { //nolint:funlen,cyclop,gocognit,gocyclo
-	var assertionOK bool
-
-	stVector := StateVector{}
-	recvDataCount := len(data)
-
-	if recvDataCount < stateVecCategoryIndex {
-		return nil, errStateVecDataCount
-	}
-
-	// Icao24 index
-	stVector.Icao24, assertionOK = data[stateVecIaco24Index].(string)
-	if !assertionOK {
-		return nil, fmt.Errorf("%w: %v", errStateVecIcao24, data[stateVecIaco24Index])
-	}
-
-	// Callsign index
-	if data[stateVecCallsignIndex] != nil {
-		val, assertionOK := data[stateVecCallsignIndex].(string)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecCallsign, data[stateVecCallsignIndex])
-		}
-
-		stVector.Callsign = &val
-	}
-
-	// OriginCountry index
-	stVector.OriginCountry, assertionOK = data[stateVecCountryIndex].(string)
-	if !assertionOK {
-		return nil, fmt.Errorf("%w: %v", errStateVecOriginCountry, data[stateVecCountryIndex])
-	}
-
-	// TimePosition index
-	if data[stateVecTimePositionIndex] != nil {
-		val, assertionOK := data[stateVecTimePositionIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecTimePosition, data[stateVecTimePositionIndex])
-		}
-
-		timePos := int64(val)
-
-		stVector.TimePosition = &timePos
-	}
-
-	// LastContact index
-	lastContact, assertionOK := data[stateVecLastContactIndex].(float64)
-	if !assertionOK {
-		return nil, fmt.Errorf("%w: %v", errStateVecLastContact, data[stateVecLastContactIndex])
+// GetOwnStates retrieves the state vectors seen by the authenticated user's own sensors for a given time.
+// If time = 0 the most recent ones are taken. The state vectors can be filtered by ICAO24 addresses
+// and by the receivers serial numbers. The Sensors field holds the IDs of the receivers which contributed.
+func (c *Client) GetOwnStates(ctx context.Context, time int64, icao24 []string, serials []int) (*States, error) {
+	if time < 0 {
+		return nil, ErrInvalidUnixTime
 	}
 
-	stVector.LastContact = int64(lastContact)
-
-	// Longitude index
-	if data[stateVecLongitudeIndex] != nil {
-		stVecLongitude, assertionOK := data[stateVecLongitudeIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecLongitude, data[stateVecLongitudeIndex])
-		}
-
-		stVector.Longitude = &stVecLongitude
+	icao24, err := normalizeICAO24(icao24)
+	if err != nil {
+		return nil, err
 	}
 
-	// Latitude index
-	if data[stateVecLatitudeIndex] != nil {
-		stVecLatitude, assertionOK := data[stateVecLatitudeIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecLatitude, data[stateVecLatitudeIndex])
-		}
+	requestParams := getStateRequestParams(time, icao24, nil, false)
 
-		stVector.Latitude = &stVecLatitude
+	for _, serial := range serials {
+		requestParams.Add("serials", strconv.Itoa(serial))
 	}
 
-	// BaroAltitude index
-	if data[stateVecBaroAltitudeIndex] != nil {
-		stVectorBaroAltitude, assertionOK := data[stateVecBaroAltitudeIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecBaroAltitude, data[stateVecBaroAltitudeIndex])
-		}
-
-		stVector.BaroAltitude = &stVectorBaroAltitude
-	}
+	return c.getStates(ctx, "GetOwnStates", "/states/own", requestParams)
+}
 
-	// OnGround index
-	stVector.OnGround, assertionOK = data[stateVecOnGroundIndex].(bool)
-	if !assertionOK {
-		return nil, fmt.Errorf("%w: %v", errStateVecOnGround, data[stateVecOnGroundIndex])
-	}
+func (c *Client) getStates(ctx context.Context, operation string, endpoint string, requestParams url.Values,
+) (*States, error) {
+	var statesRep statesResponse
 
-	// Velocity index
-	if data[stateVecVelocityIndex] != nil {
-		stVectorVelocity, assertionOK := data[stateVecVelocityIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecVelocity, data[stateVecVelocityIndex])
-		}
+	ctx, call := c.conn.startCall(ctx, operation, endpoint, requestParams)
 
-		stVector.Velocity = &stVectorVelocity
-	}
+	err := c.conn.getJSON(ctx, call, &statesRep)
+	if err != nil {
+		var stvecErr *StateVectorError
+		if errors.As(err, &stvecErr) {
+			c.conn.logStateVectorError(ctx, endpoint, requestParams, stvecErr)
 
-	// TrueTrack index
-	if data[stateVecTrueTrackIndex] != nil {
-		stVectorTrueTrack, assertionOK := data[stateVecTrueTrackIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecTrueTrack, data[stateVecTrueTrackIndex])
+			err = fmt.Errorf("decode state vector: %w", stvecErr)
 		}
 
-		stVector.TrueTrack = &stVectorTrueTrack
-	}
-
-	// VerticalRate index
-	if data[stateVecVerticalRateIndex] != nil {
-		stVectorVerticalRate, assertionOK := data[stateVecVerticalRateIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecVerticalRate, data[stateVecVerticalRateIndex])
-		}
+		call.end(0, err)
 
-		stVector.VerticalRate = &stVectorVerticalRate
+		return nil, err
 	}
 
-	// Sensors index
-	if data[stateVecSensorsIndex] != nil {
-		stVector.Sensors, assertionOK = data[stateVecSensorsIndex].([]int)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecSensors, data[stateVecSensorsIndex])
-		}
+	states := statesRep.states()
+	if states.States == nil {
+		states.States = make([]StateVector, 0)
 	}
 
-	// GeoAltitude index
-	if data[stateVecGeoAltitudeIndex] != nil {
-		stVectorGeoAltitude, assertionOK := data[stateVecGeoAltitudeIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecGeoAltitude, data[stateVecGeoAltitudeIndex])
-		}
+	call.end(len(states.States), nil)
 
-		stVector.GeoAltitude = &stVectorGeoAltitude
-	}
-
-	// Squawk index
-	if data[stateVecSquawkIndex] != nil {
-		stVectorSquawk, assertionOK := data[stateVecSquawkIndex].(string)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecSquawk, data[stateVecSquawkIndex])
-		}
+	return states, nil
+}
 
-		stVector.Squawk = &stVectorSquawk
+func getStateRequestParams(time int64, icao24 []string, bBox *BoundingBoxOptions, extended bool) url.Values {
+	requestParams := make(url.Values)
+	if time > 0 {
+		requestParams.Add("time", fmt.Sprintf("%d", time)) //nolint:perfsprint
 	}
 
-	// Spi index
-	stVector.Spi, assertionOK = data[stateVecSpiIndex].(bool)
-	if !assertionOK {
-		return nil, fmt.Errorf("%w: %v", errStateVecSpi, data[stateVecSpiIndex])
+	for _, icao24Item := range icao24 {
+		requestParams.Add("icao24", icao24Item)
 	}
 
-	// PositionSource index
-	stVectorPositionSource, assertionOK := data[stateVecPositionSourceIndex].(float64)
-	if !assertionOK {
-		return nil, fmt.Errorf("%w: %v", errStateVecPositionSource, data[stateVecPositionSourceIndex])
+	if extended {
+		requestParams.Add("extended", "1")
 	}
 
-	stVector.PositionSource = int(stVectorPositionSource)
-
-	// Category index
-	if recvDataCount == stateVecCategoryIndex+1 {
-		stVectorCategory, assertionOK := data[stateVecCategoryIndex].(float64)
-		if !assertionOK {
-			return nil, fmt.Errorf("%w: %v", errStateVecCategory, data[stateVecCategoryIndex])
-		}
-
-		stVector.Category = int(stVectorCategory)
+	if bBox != nil {
+		requestParams.Add("lamax", floatToString(bBox.Lamax))
+		requestParams.Add("lamin", floatToString(bBox.Lamin))
+		requestParams.Add("lomax", floatToString(bBox.Lomax))
+		requestParams.Add("lomin", floatToString(bBox.Lomin))
 	}
 
-	return &stVector, nil
+	return requestParams
 }
 
 // NewBoundingBox returns new bounding box options for states information gathering.
@@ -0,0 +1,498 @@
+package gopensky
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// stateVecFields are the names and errors of the state vector positional fields.
+var stateVecFields = [...]struct { //nolint:gochecknoglobals
+	name string
+	err  error
+}{
+	stateVecIaco24Index:         {name: "icao24", err: errStateVecIcao24},
+	stateVecCallsignIndex:       {name: "callsign", err: errStateVecCallsign},
+	stateVecCountryIndex:        {name: "originCountry", err: errStateVecOriginCountry},
+	stateVecTimePositionIndex:   {name: "timePosition", err: errStateVecTimePosition},
+	stateVecLastContactIndex:    {name: "lastContact", err: errStateVecLastContact},
+	stateVecLongitudeIndex:      {name: "longitude", err: errStateVecLongitude},
+	stateVecLatitudeIndex:       {name: "latitude", err: errStateVecLatitude},
+	stateVecBaroAltitudeIndex:   {name: "baroAltitude", err: errStateVecBaroAltitude},
+	stateVecOnGroundIndex:       {name: "onGround", err: errStateVecOnGround},
+	stateVecVelocityIndex:       {name: "velocity", err: errStateVecVelocity},
+	stateVecTrueTrackIndex:      {name: "trueTrack", err: errStateVecTrueTrack},
+	stateVecVerticalRateIndex:   {name: "verticalRate", err: errStateVecVerticalRate},
+	stateVecSensorsIndex:        {name: "sensors", err: errStateVecSensors},
+	stateVecGeoAltitudeIndex:    {name: "geoAltitude", err: errStateVecGeoAltitude},
+	stateVecSquawkIndex:         {name: "squawk", err: errStateVecSquawk},
+	stateVecSpiIndex:            {name: "spi", err: errStateVecSpi},
+	stateVecPositionSourceIndex: {name: "positionSource", err: errStateVecPositionSource},
+	stateVecCategoryIndex:       {name: "category", err: errStateVecCategory},
+}
+
+// statesResponse is the states API response decoded with the typed state vector decoder.
+type statesResponse struct {
+	Time   int64           `json:"time"`
+	States stateVectorRows `json:"states"`
+}
+
+// states returns the decoded states.
+func (r *statesResponse) states() *States {
+	return &States{
+		Time:     r.Time,
+		States:   r.States.stateVectors,
+		Extended: r.States.extended,
+	}
+}
+
+// stateVectorRows is the state vectors list of a states response.
+// It decodes the positional JSON rows straight into the state vector fields, without the intermediate
+// [][]any values. The strings share a single copy of the payload and the optional values are allocated
+// in chunks, so a 10k rows response is decoded with about 130 allocations instead of 530k (benchmark).
+// The decoded state vectors share the memory of the payload copy and of the chunks.
+type stateVectorRows struct {
+	stateVectors []StateVector
+
+	// extended is true if the rows have the category field.
+	extended bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (rows *stateVectorRows) UnmarshalJSON(data []byte) error {
+	scanner := newJSONScanner(data)
+
+	if scanner.null() {
+		*rows = stateVectorRows{}
+
+		return nil
+	}
+
+	if !scanner.consume('[') {
+		return fmt.Errorf("%w: %s", errUnexpectedJSONToken, data)
+	}
+
+	stateVectors := make([]StateVector, 0)
+
+	for index := 0; !scanner.consume(']'); index++ {
+		if index > 0 && !scanner.consume(',') {
+			return fmt.Errorf("%w: %s", errUnexpectedJSONToken, scanner.rest())
+		}
+
+		stateVectors = append(stateVectors, StateVector{})
+
+		fields, err := decodeStateVectorRow(&scanner, &stateVectors[index])
+		if err != nil {
+			err.Row = index
+
+			return err
+		}
+
+		rows.extended = rows.extended || fields > stateVecCategoryIndex
+	}
+
+	rows.stateVectors = stateVectors
+
+	return nil
+}
+
+// stateVectorRow is a single positional state vector row.
+type stateVectorRow StateVector
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (row *stateVectorRow) UnmarshalJSON(data []byte) error {
+	scanner := newJSONScanner(data)
+
+	_, err := decodeStateVectorRow(&scanner, (*StateVector)(row))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// decodeStateVectorRow decodes a positional state vector row from the scanner
+// and returns its number of fields.
+func decodeStateVectorRow(scanner *jsonScanner, stVector *StateVector) (int, *StateVectorError) {
+	if !scanner.consume('[') {
+		return 0, &StateVectorError{Index: -1, Err: fmt.Errorf("%w: %s", errUnexpectedJSONToken, scanner.rest())}
+	}
+
+	for index := 0; ; index++ {
+		if scanner.consume(']') {
+			if index < stateVecCategoryIndex {
+				return index, &StateVectorError{Index: index, Err: errStateVecDataCount}
+			}
+
+			return index, nil
+		}
+
+		if index > 0 && !scanner.consume(',') {
+			return index, &StateVectorError{
+				Index: index, Err: fmt.Errorf("%w: %s", errUnexpectedJSONToken, scanner.rest()),
+			}
+		}
+
+		if err := decodeStateVectorField(scanner, index, stVector); err != nil {
+			return index, err
+		}
+	}
+}
+
+func decodeStateVectorField( //nolint:cyclop
+	scanner *jsonScanner, index int, stVector *StateVector,
+) *StateVectorError {
+	scanner.skipSpace()
+	start := scanner.pos
+
+	var valid bool
+
+	switch index {
+	case stateVecIaco24Index:
+		stVector.Icao24, valid = scanner.readString()
+	case stateVecCallsignIndex:
+		stVector.Callsign, valid = scanner.readOptString()
+	case stateVecCountryIndex:
+		stVector.OriginCountry, valid = scanner.readString()
+	case stateVecTimePositionIndex:
+		stVector.TimePosition, valid = scanner.readOptInt64()
+	case stateVecLastContactIndex:
+		stVector.LastContact, valid = scanner.readInt64()
+	case stateVecLongitudeIndex:
+		stVector.Longitude, valid = scanner.readOptFloat64()
+	case stateVecLatitudeIndex:
+		stVector.Latitude, valid = scanner.readOptFloat64()
+	case stateVecBaroAltitudeIndex:
+		stVector.BaroAltitude, valid = scanner.readOptFloat64()
+	case stateVecOnGroundIndex:
+		stVector.OnGround, valid = scanner.readBool()
+	case stateVecVelocityIndex:
+		stVector.Velocity, valid = scanner.readOptFloat64()
+	case stateVecTrueTrackIndex:
+		stVector.TrueTrack, valid = scanner.readOptFloat64()
+	case stateVecVerticalRateIndex:
+		stVector.VerticalRate, valid = scanner.readOptFloat64()
+	case stateVecSensorsIndex:
+		stVector.Sensors, valid = scanner.readOptInts()
+	case stateVecGeoAltitudeIndex:
+		stVector.GeoAltitude, valid = scanner.readOptFloat64()
+	case stateVecSquawkIndex:
+		stVector.Squawk, valid = scanner.readOptString()
+	case stateVecSpiIndex:
+		stVector.Spi, valid = scanner.readBool()
+	case stateVecPositionSourceIndex:
+		var positionSource int
+		positionSource, valid = scanner.readInt()
+		stVector.PositionSource = PositionSource(positionSource)
+	case stateVecCategoryIndex:
+		var category int
+		category, valid = scanner.readInt()
+		stVector.Category = AircraftCategory(category)
+	default:
+		// fields added by future API versions are ignored.
+		valid = scanner.skipValue()
+	}
+
+	if valid {
+		return nil
+	}
+
+	if index >= len(stateVecFields) {
+		return &StateVectorError{Index: index, Err: fmt.Errorf("%w: %s", errUnexpectedJSONToken, scanner.rest())}
+	}
+
+	scanner.pos = start
+	scanner.skipValue()
+
+	return &StateVectorError{
+		Index: index,
+		Field: stateVecFields[index].name,
+		Err:   fmt.Errorf("%w: %s", stateVecFields[index].err, scanner.data[start:scanner.pos]),
+	}
+}
+
+// scannerChunkSize is the number of optional values allocated at once by the jsonScanner.
+const scannerChunkSize = 1024
+
+// jsonScanner reads JSON values from a syntactically valid JSON document.
+// The value methods return false, without consuming the value, if the value type doesn't match.
+type jsonScanner struct {
+	data []byte
+	pos  int
+
+	// text is the document as a string, the unescaped string values are sliced from it.
+	text string
+
+	// chunks of the optional values, the returned pointers point into them.
+	floatChunk  []float64
+	intChunk    []int64
+	stringChunk []string
+}
+
+func newJSONScanner(data []byte) jsonScanner {
+	return jsonScanner{data: data, text: string(data)}
+}
+
+// chunkValue returns a pointer to the value stored in the chunk, allocating a new chunk when full.
+func chunkValue[T any](chunk *[]T, value T) *T {
+	if len(*chunk) == cap(*chunk) {
+		*chunk = make([]T, 0, scannerChunkSize)
+	}
+
+	*chunk = append(*chunk, value)
+
+	return &(*chunk)[len(*chunk)-1]
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *jsonScanner) peek() byte {
+	s.skipSpace()
+
+	if s.pos >= len(s.data) {
+		return 0
+	}
+
+	return s.data[s.pos]
+}
+
+// consume consumes the given delimiter if it's the next character.
+func (s *jsonScanner) consume(delim byte) bool {
+	if s.peek() != delim {
+		return false
+	}
+
+	s.pos++
+
+	return true
+}
+
+func (s *jsonScanner) literal(value string) bool {
+	s.skipSpace()
+
+	if len(s.data)-s.pos < len(value) || string(s.data[s.pos:s.pos+len(value)]) != value {
+		return false
+	}
+
+	s.pos += len(value)
+
+	return true
+}
+
+func (s *jsonScanner) null() bool {
+	return s.literal("null")
+}
+
+func (s *jsonScanner) rest() []byte {
+	return s.data[min(s.pos, len(s.data)):]
+}
+
+func (s *jsonScanner) readBool() (bool, bool) {
+	if s.literal("true") {
+		return true, true
+	}
+
+	return false, s.literal("false")
+}
+
+func (s *jsonScanner) readString() (string, bool) {
+	if s.peek() != '"' {
+		return "", false
+	}
+
+	start := s.pos
+	escaped := false
+
+	for s.pos++; s.pos < len(s.data); s.pos++ {
+		switch s.data[s.pos] {
+		case '\\':
+			escaped = true
+			s.pos++
+		case '"':
+			s.pos++
+
+			if !escaped {
+				return s.text[start+1 : s.pos-1], true
+			}
+
+			var value string
+			if err := json.Unmarshal(s.data[start:s.pos], &value); err != nil {
+				s.pos = start
+
+				return "", false
+			}
+
+			return value, true
+		}
+	}
+
+	s.pos = start
+
+	return "", false
+}
+
+func (s *jsonScanner) readOptString() (*string, bool) {
+	if s.null() {
+		return nil, true
+	}
+
+	value, valid := s.readString()
+	if !valid {
+		return nil, false
+	}
+
+	return chunkValue(&s.stringChunk, value), true
+}
+
+func (s *jsonScanner) readFloat64() (float64, bool) {
+	s.skipSpace()
+	start := s.pos
+
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '-', '+', '.', 'e', 'E', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			s.pos++
+
+			continue
+		}
+
+		break
+	}
+
+	value, err := strconv.ParseFloat(s.text[start:s.pos], 64)
+	if err != nil {
+		s.pos = start
+
+		return 0, false
+	}
+
+	return value, true
+}
+
+func (s *jsonScanner) readOptFloat64() (*float64, bool) {
+	if s.null() {
+		return nil, true
+	}
+
+	value, valid := s.readFloat64()
+	if !valid {
+		return nil, false
+	}
+
+	return chunkValue(&s.floatChunk, value), true
+}
+
+func (s *jsonScanner) readInt64() (int64, bool) {
+	value, valid := s.readFloat64()
+
+	return int64(value), valid
+}
+
+func (s *jsonScanner) readOptInt64() (*int64, bool) {
+	if s.null() {
+		return nil, true
+	}
+
+	value, valid := s.readInt64()
+	if !valid {
+		return nil, false
+	}
+
+	return chunkValue(&s.intChunk, value), true
+}
+
+func (s *jsonScanner) readInt() (int, bool) {
+	value, valid := s.readFloat64()
+
+	return int(value), valid
+}
+
+func (s *jsonScanner) readOptInts() ([]int, bool) {
+	if s.null() {
+		return nil, true
+	}
+
+	start := s.pos
+
+	if !s.consume('[') {
+		return nil, false
+	}
+
+	values := make([]int, 0)
+
+	for index := 0; !s.consume(']'); index++ {
+		if index > 0 && !s.consume(',') {
+			s.pos = start
+
+			return nil, false
+		}
+
+		value, valid := s.readInt()
+		if !valid {
+			s.pos = start
+
+			return nil, false
+		}
+
+		values = append(values, value)
+	}
+
+	return values, true
+}
+
+// skipValue skips the next value, including the nested arrays and objects.
+func (s *jsonScanner) skipValue() bool {
+	depth := 0
+
+	for s.skipSpace(); s.pos < len(s.data); {
+		switch char := s.data[s.pos]; char {
+		case '"':
+			if _, valid := s.readString(); !valid {
+				return false
+			}
+		case '[', '{':
+			depth++
+			s.pos++
+		case ']', '}':
+			if depth == 0 {
+				return true
+			}
+
+			depth--
+			s.pos++
+		case ',':
+			if depth == 0 {
+				return true
+			}
+
+			s.pos++
+		default:
+			s.pos++
+		}
+
+		if depth == 0 && s.isValueEnd() {
+			return true
+		}
+	}
+
+	return depth == 0
+}
+
+// isValueEnd returns true if the next character ends the current value.
+func (s *jsonScanner) isValueEnd() bool {
+	switch s.peek() {
+	case ',', ']', '}', ':', 0:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,134 @@
+package gopensky_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("States decoder", func() {
+	It("decodes the same state vectors as decodeRawStateVector", func() {
+		data, err := os.ReadFile("mock_data/all_states.json")
+		Expect(err).NotTo(HaveOccurred())
+
+		states, err := gopensky.DecodeStatesResponse(data)
+		Expect(err).NotTo(HaveOccurred())
+
+		var statesRep gopensky.StatesResponse
+
+		Expect(json.Unmarshal(data, &statesRep)).To(Succeed())
+		Expect(states.Time).To(Equal(statesRep.Time))
+		Expect(states.States).To(HaveLen(len(statesRep.States)))
+
+		for index, row := range statesRep.States {
+			stvec, err := gopensky.DecodeRawStateVector(row)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(states.States[index]).To(Equal(*stvec))
+		}
+	})
+
+	It("decodes the sensors, escaped strings and extra fields", func() {
+		states, err := gopensky.DecodeStatesResponse([]byte(`{"time": 1, "states": [
+			["ac96b8", "AAL22423", "United States", null, 1518552809, null, null, null, true,
+				null, null, null, [12, 34], null, null, false, 2, 8, "future field"]
+		]}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(states.States).To(HaveLen(1))
+
+		stvec := states.States[0]
+		Expect(*stvec.Callsign).To(Equal("AAL22423"))
+		Expect(stvec.TimePosition).To(BeNil())
+		Expect(stvec.OnGround).To(BeTrue())
+		Expect(stvec.Sensors).To(Equal([]int{12, 34}))
+		Expect(stvec.PositionSource).To(Equal(gopensky.PositionSourceMLAT))
+		Expect(stvec.Category).To(Equal(gopensky.CategoryRotorcraft))
+	})
+
+	It("returns the row and field of the invalid values", func() {
+		_, err := gopensky.DecodeStatesResponse([]byte(`{"time": 1, "states": [
+			["ac96b8", null, "Switzerland", 1, 1, null, null, null, false, null, null, null, null, null, null, false, 0],
+			["ac96b9", null, "Switzerland", 1, 1, "7.1", null, null, false, null, null, null, null, null, null, false, 0]
+		]}`))
+
+		var stvecErr *gopensky.StateVectorError
+		Expect(errors.As(err, &stvecErr)).To(BeTrue())
+		Expect(stvecErr.Row).To(Equal(1))
+		Expect(stvecErr.Index).To(Equal(5))
+		Expect(stvecErr.Field).To(Equal("longitude"))
+		Expect(err.Error()).To(Equal(`row 1 field 5 (longitude): state vector longitude assertion failed: "7.1"`))
+	})
+
+	It("decodes the null states", func() {
+		states, err := gopensky.DecodeStatesResponse([]byte(`{"time": 1, "states": null}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(states.States).To(BeNil())
+	})
+})
+
+// statesPayload returns a states response of count realistic state vectors.
+func statesPayload(count int) []byte {
+	var builder strings.Builder
+
+	builder.WriteString(`{"time": 1696755342, "states": [`)
+
+	for index := range count {
+		if index > 0 {
+			builder.WriteString(",")
+		}
+
+		fmt.Fprintf(&builder, `["%06x", "SWR%-5d", "Switzerland", 1696755340, 1696755341, %f, %f, 11277.6, false,`+
+			` 231.2, 287.51, -0.33, null, 11612.88, "1000", false, 0, 4]`,
+			index, index%10000, 8.5+float64(index%100)/100, 47.4+float64(index%50)/100)
+	}
+
+	builder.WriteString("]}")
+
+	return []byte(builder.String())
+}
+
+func BenchmarkDecodeStatesRaw(b *testing.B) {
+	data := statesPayload(10000)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		var statesRep gopensky.StatesResponse
+
+		if err := json.Unmarshal(data, &statesRep); err != nil {
+			b.Fatal(err)
+		}
+
+		stateVectors := make([]gopensky.StateVector, 0, len(statesRep.States))
+
+		for _, row := range statesRep.States {
+			stvec, err := gopensky.DecodeRawStateVector(row)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			stateVectors = append(stateVectors, *stvec)
+		}
+	}
+}
+
+func BenchmarkDecodeStatesTyped(b *testing.B) {
+	data := statesPayload(10000)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if _, err := gopensky.DecodeStatesResponse(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package gopensky
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/navidys/gopensky/geo"
+)
+
+// DistanceTo returns the great-circle distance in meters from the state vector position to the given position.
+// It returns false if the state vector has no position.
+func (s *StateVector) DistanceTo(lat float64, lon float64) (float64, bool) {
+	if s.Latitude == nil || s.Longitude == nil {
+		return 0, false
+	}
+
+	return geo.Distance(*s.Latitude, *s.Longitude, lat, lon), true
+}
+
+// BearingTo returns the initial bearing in decimal degrees clockwise from north from the state vector position
+// to the given position. It returns false if the state vector has no position.
+func (s *StateVector) BearingTo(lat float64, lon float64) (float64, bool) {
+	if s.Latitude == nil || s.Longitude == nil {
+		return 0, false
+	}
+
+	return geo.Bearing(*s.Latitude, *s.Longitude, lat, lon), true
+}
+
+// GetStatesWithinRadius retrieves the state vectors within the radius in meters of a position for a given time.
+// If time = 0 the most recent ones are taken. The states of the enclosing bounding box are requested
+// and filtered by their great-circle distance, the state vectors without position are left out.
+func GetStatesWithinRadius(ctx context.Context, time int64, lat float64, lon float64, radius float64,
+	extended bool,
+) (*States, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetStatesWithinRadius(ctx, time, lat, lon, radius, extended)
+}
+
+// GetStatesWithinRadius retrieves the state vectors within the radius in meters of a position for a given time.
+// If time = 0 the most recent ones are taken. The states of the enclosing bounding box are requested
+// and filtered by their great-circle distance, the state vectors without position are left out.
+func (c *Client) GetStatesWithinRadius(ctx context.Context, time int64, lat float64, lon float64, radius float64,
+	extended bool,
+) (*States, error) {
+	if radius <= 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRadius, radius)
+	}
+
+	box := geo.BoundingBoxAround(lat, lon, radius)
+
+	states, err := c.GetStates(ctx, time, nil,
+		NewBoundingBox(box.MinLatitude, box.MinLongitude, box.MaxLatitude, box.MaxLongitude), extended)
+	if err != nil {
+		return nil, err
+	}
+
+	withinRadius := make([]StateVector, 0, len(states.States))
+
+	for _, stvec := range states.States {
+		if distance, ok := stvec.DistanceTo(lat, lon); ok && distance <= radius {
+			withinRadius = append(withinRadius, stvec)
+		}
+	}
+
+	states.States = withinRadius
+
+	return states, nil
+}
@@ -0,0 +1,118 @@
+package gopensky_test
+
+import (
+	"context"
+
+	"github.com/h2non/gock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("States geo", func() {
+	const bwiLat, bwiLon = 39.1754, -76.6683
+
+	It("returns the distance and bearing to a position", func() {
+		latitude, longitude := 39.2978, -76.0575
+		stvec := gopensky.StateVector{Latitude: &latitude, Longitude: &longitude}
+
+		distance, ok := stvec.DistanceTo(bwiLat, bwiLon)
+		Expect(ok).To(BeTrue())
+		Expect(distance).To(BeNumerically("~", 54300, 500))
+
+		bearing, ok := stvec.BearingTo(bwiLat, bwiLon)
+		Expect(ok).To(BeTrue())
+		Expect(bearing).To(BeNumerically("~", 255, 1))
+
+		stvec.Latitude = nil
+
+		_, ok = stvec.DistanceTo(bwiLat, bwiLon)
+		Expect(ok).To(BeFalse())
+
+		_, ok = stvec.BearingTo(bwiLat, bwiLon)
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("GetStatesWithinRadius", func() {
+		It("retrieves the state vectors within the radius", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer gock.Off()
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lamin", `^38\.27`).
+				MatchParam("lamax", `^40\.07`).
+				MatchParam("lomin", `^-77\.82`).
+				MatchParam("lomax", `^-75\.50`).
+				Reply(200).
+				File("mock_data/all_states.json")
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			gock.InterceptClient(gclient)
+
+			states, err := gopensky.GetStatesWithinRadius(conn, 0, bwiLat, bwiLon, 100000, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gock.IsDone()).To(BeTrue())
+			Expect(states.States).To(HaveLen(1))
+			Expect(states.States[0].Icao24).To(Equal("aa56da"))
+		})
+
+		It("splits the bounding box if the circle crosses the antimeridian", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer gock.Off()
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^178\.10`).
+				MatchParam("lomax", `^180\.0`).
+				Reply(200).
+				BodyString(`{"time": 1696755342, "states": [
+					["c00001", "WEST ", "Canada", 1696755342, 1696755342, 179.95, 60.0, 1000.0, false, 200.0,
+					 90.0, 0.0, null, 1100.0, null, false, 0],
+					["c00002", "EAST ", "Canada", 1696755342, 1696755342, 178.2, 60.85, 1000.0, false, 200.0,
+					 90.0, 0.0, null, 1100.0, null, false, 0]]}`)
+
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/all").
+				MatchParam("lomin", `^-180\.0`).
+				MatchParam("lomax", `^-178\.30`).
+				Reply(200).
+				BodyString(`{"time": 1696755343, "states": [
+					["c00003", "EAST ", "Canada", 1696755343, 1696755343, -179.95, 60.0, 1000.0, false, 200.0,
+					 90.0, 0.0, null, 1100.0, null, false, 0]]}`)
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			gock.InterceptClient(gclient)
+
+			states, err := gopensky.GetStatesWithinRadius(conn, 0, 60, 179.9, 100000, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gock.IsDone()).To(BeTrue())
+			Expect(states.Time).To(Equal(int64(1696755343)))
+			Expect(states.States).To(HaveLen(2))
+			Expect(states.States[0].Icao24).To(Equal("c00001"))
+			Expect(states.States[1].Icao24).To(Equal("c00003"))
+		})
+
+		It("tests GetStatesWithinRadius errors", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gopensky.GetStatesWithinRadius(context.Background(), 0, bwiLat, bwiLon, 1000, false)
+			Expect(err.Error()).To(ContainSubstring("invalid context key"))
+
+			_, err = gopensky.GetStatesWithinRadius(conn, 0, bwiLat, bwiLon, 0, false)
+			Expect(err).To(MatchError(gopensky.ErrInvalidRadius))
+
+			_, err = gopensky.GetStatesWithinRadius(conn, -1, bwiLat, bwiLon, 1000, false)
+			Expect(err).To(Equal(gopensky.ErrInvalidUnixTime))
+
+			_, err = gopensky.GetStatesWithinRadius(conn, 0, 95, bwiLon, 1000, false)
+			Expect(err).To(MatchError(gopensky.ErrInvalidBoundingBox))
+		})
+	})
+})
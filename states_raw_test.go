@@ -0,0 +1,203 @@
+package gopensky
+
+import (
+	"fmt"
+)
+
+// decodeRawStateVector is the legacy state vector decoder of the [][]any rows (StatesResponse).
+// It's the reference implementation the typed decoder is tested and benchmarked against.
+func decodeRawStateVector(data []any) (*StateVector, error) { //nolint:funlen,cyclop,gocognit,gocyclo
+	var assertionOK bool
+
+	stVector := StateVector{}
+	recvDataCount := len(data)
+
+	if recvDataCount < stateVecCategoryIndex {
+		return nil, errStateVecDataCount
+	}
+
+	// Icao24 index
+	stVector.Icao24, assertionOK = data[stateVecIaco24Index].(string)
+	if !assertionOK {
+		return nil, fmt.Errorf("%w: %v", errStateVecIcao24, data[stateVecIaco24Index])
+	}
+
+	// Callsign index
+	if data[stateVecCallsignIndex] != nil {
+		val, assertionOK := data[stateVecCallsignIndex].(string)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecCallsign, data[stateVecCallsignIndex])
+		}
+
+		stVector.Callsign = &val
+	}
+
+	// OriginCountry index
+	stVector.OriginCountry, assertionOK = data[stateVecCountryIndex].(string)
+	if !assertionOK {
+		return nil, fmt.Errorf("%w: %v", errStateVecOriginCountry, data[stateVecCountryIndex])
+	}
+
+	// TimePosition index
+	if data[stateVecTimePositionIndex] != nil {
+		val, assertionOK := data[stateVecTimePositionIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecTimePosition, data[stateVecTimePositionIndex])
+		}
+
+		timePos := int64(val)
+
+		stVector.TimePosition = &timePos
+	}
+
+	// LastContact index
+	lastContact, assertionOK := data[stateVecLastContactIndex].(float64)
+	if !assertionOK {
+		return nil, fmt.Errorf("%w: %v", errStateVecLastContact, data[stateVecLastContactIndex])
+	}
+
+	stVector.LastContact = int64(lastContact)
+
+	// Longitude index
+	if data[stateVecLongitudeIndex] != nil {
+		stVecLongitude, assertionOK := data[stateVecLongitudeIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecLongitude, data[stateVecLongitudeIndex])
+		}
+
+		stVector.Longitude = &stVecLongitude
+	}
+
+	// Latitude index
+	if data[stateVecLatitudeIndex] != nil {
+		stVecLatitude, assertionOK := data[stateVecLatitudeIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecLatitude, data[stateVecLatitudeIndex])
+		}
+
+		stVector.Latitude = &stVecLatitude
+	}
+
+	// BaroAltitude index
+	if data[stateVecBaroAltitudeIndex] != nil {
+		stVectorBaroAltitude, assertionOK := data[stateVecBaroAltitudeIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecBaroAltitude, data[stateVecBaroAltitudeIndex])
+		}
+
+		stVector.BaroAltitude = &stVectorBaroAltitude
+	}
+
+	// OnGround index
+	stVector.OnGround, assertionOK = data[stateVecOnGroundIndex].(bool)
+	if !assertionOK {
+		return nil, fmt.Errorf("%w: %v", errStateVecOnGround, data[stateVecOnGroundIndex])
+	}
+
+	// Velocity index
+	if data[stateVecVelocityIndex] != nil {
+		stVectorVelocity, assertionOK := data[stateVecVelocityIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecVelocity, data[stateVecVelocityIndex])
+		}
+
+		stVector.Velocity = &stVectorVelocity
+	}
+
+	// TrueTrack index
+	if data[stateVecTrueTrackIndex] != nil {
+		stVectorTrueTrack, assertionOK := data[stateVecTrueTrackIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecTrueTrack, data[stateVecTrueTrackIndex])
+		}
+
+		stVector.TrueTrack = &stVectorTrueTrack
+	}
+
+	// VerticalRate index
+	if data[stateVecVerticalRateIndex] != nil {
+		stVectorVerticalRate, assertionOK := data[stateVecVerticalRateIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecVerticalRate, data[stateVecVerticalRateIndex])
+		}
+
+		stVector.VerticalRate = &stVectorVerticalRate
+	}
+
+	// Sensors index
+	if data[stateVecSensorsIndex] != nil {
+		stVector.Sensors, assertionOK = decodeRawSensors(data[stateVecSensorsIndex])
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecSensors, data[stateVecSensorsIndex])
+		}
+	}
+
+	// GeoAltitude index
+	if data[stateVecGeoAltitudeIndex] != nil {
+		stVectorGeoAltitude, assertionOK := data[stateVecGeoAltitudeIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecGeoAltitude, data[stateVecGeoAltitudeIndex])
+		}
+
+		stVector.GeoAltitude = &stVectorGeoAltitude
+	}
+
+	// Squawk index
+	if data[stateVecSquawkIndex] != nil {
+		stVectorSquawk, assertionOK := data[stateVecSquawkIndex].(string)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecSquawk, data[stateVecSquawkIndex])
+		}
+
+		stVector.Squawk = &stVectorSquawk
+	}
+
+	// Spi index
+	stVector.Spi, assertionOK = data[stateVecSpiIndex].(bool)
+	if !assertionOK {
+		return nil, fmt.Errorf("%w: %v", errStateVecSpi, data[stateVecSpiIndex])
+	}
+
+	// PositionSource index
+	stVectorPositionSource, assertionOK := data[stateVecPositionSourceIndex].(float64)
+	if !assertionOK {
+		return nil, fmt.Errorf("%w: %v", errStateVecPositionSource, data[stateVecPositionSourceIndex])
+	}
+
+	stVector.PositionSource = PositionSource(stVectorPositionSource)
+
+	// Category index
+	if recvDataCount == stateVecCategoryIndex+1 {
+		stVectorCategory, assertionOK := data[stateVecCategoryIndex].(float64)
+		if !assertionOK {
+			return nil, fmt.Errorf("%w: %v", errStateVecCategory, data[stateVecCategoryIndex])
+		}
+
+		stVector.Category = AircraftCategory(stVectorCategory)
+	}
+
+	return &stVector, nil
+}
+
+// decodeRawSensors returns the sensors IDs of a decoded JSON array ([]any of float64).
+func decodeRawSensors(data any) ([]int, bool) {
+	switch sensors := data.(type) {
+	case []int:
+		return sensors, true
+	case []any:
+		ids := make([]int, 0, len(sensors))
+
+		for _, sensor := range sensors {
+			id, ok := sensor.(float64)
+			if !ok {
+				return nil, false
+			}
+
+			ids = append(ids, int(id))
+		}
+
+		return ids, true
+	default:
+		return nil, false
+	}
+}
@@ -50,7 +50,38 @@ var _ = Describe("States", func() {
 			Expect(*firstState.GeoAltitude).To(Equal(float64(1143)))
 			Expect(*firstState.Squawk).To(Equal("2236"))
 			Expect(firstState.Spi).To(Equal(false))
-			Expect(firstState.PositionSource).To(Equal(0))
+			Expect(firstState.PositionSource).To(Equal(gopensky.PositionSourceADSB))
+		})
+	})
+
+	Describe("GetOwnStates", func() {
+		It("retrieve own sensors state vectors", func() {
+			conn, err := gopensky.NewConnection(context.Background(), "user", "password")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer gock.Off()
+			gock.New(gopensky.OpenSkyAPIURL).
+				Get("/states/own").
+				MatchParam("serials", "1359").
+				MatchParam("icao24", "ac96b8").
+				MatchHeader("Authorization", "^Basic ").
+				Reply(200).
+				File("mock_data/own_states.json")
+
+			gclient, err := gopensky.GetClient(conn)
+			Expect(err).NotTo(HaveOccurred())
+			gock.InterceptClient(gclient)
+
+			states, err := gopensky.GetOwnStates(conn, 0, []string{"ac96b8"}, []int{1359})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(states.Time).To(Equal(int64(1518552809)))
+			Expect(states.States).To(HaveLen(1))
+			Expect(states.States[0].Sensors).To(Equal([]int{1408, 1359}))
+
+			_, err = gopensky.GetOwnStates(conn, -1, nil, nil)
+			Expect(err).To(Equal(gopensky.ErrInvalidUnixTime))
+
+			Expect(gopensky.RequestCost("/states/own", nil)).To(Equal(0))
 		})
 	})
 
@@ -92,7 +123,7 @@ var _ = Describe("States", func() {
 				File("mock_data/errors/states01.json")
 
 			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
-			Expect(errors.Unwrap(err).Error()).To(Equal("invalid state vector data count"))
+			Expect(errors.Unwrap(err).Error()).To(Equal("row 0: invalid state vector data count"))
 
 			// icao24 assertion error
 			gock.New(gopensky.OpenSkyAPIURL).
@@ -247,6 +278,13 @@ var _ = Describe("States", func() {
 			_, err = gopensky.GetStates(conn, 0, nil, nil, false)
 			Expect(errors.Unwrap(err).Error()).To(ContainSubstring("sensors assertion"))
 
+			var stvecErr *gopensky.StateVectorError
+			Expect(errors.As(err, &stvecErr)).To(BeTrue())
+			Expect(stvecErr.Row).To(Equal(0))
+			Expect(stvecErr.Index).To(Equal(12))
+			Expect(stvecErr.Field).To(Equal("sensors"))
+			Expect(stvecErr.Error()).To(Equal(`row 0 field 12 (sensors): state vector sensors assertion failed: "a"`))
+
 		})
 	})
 
@@ -350,8 +388,8 @@ var _ = Describe("States", func() {
 						GeoAltitude:    &testFloatValue,
 						Squawk:         &testStringValue,
 						Spi:            false,
-						PositionSource: testIntValue,
-						Category:       testIntValue,
+						PositionSource: gopensky.PositionSource(testIntValue),
+						Category:       gopensky.AircraftCategory(testIntValue),
 					},
 				},
 				{
@@ -392,8 +430,8 @@ var _ = Describe("States", func() {
 						GeoAltitude:    nil,
 						Squawk:         nil,
 						Spi:            false,
-						PositionSource: testIntValue,
-						Category:       testIntValue,
+						PositionSource: gopensky.PositionSource(testIntValue),
+						Category:       gopensky.AircraftCategory(testIntValue),
 					},
 				},
 			}
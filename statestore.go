@@ -0,0 +1,340 @@
+package gopensky
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/navidys/gopensky/geo"
+)
+
+const (
+	defaultStoreMaxSamples = 360
+	defaultStoreMaxAge     = time.Hour
+
+	// initial capacity of the aircraft history buffers.
+	minStateRingCapacity = 8
+)
+
+// StateStoreOption is a configuration option of the StateStore.
+type StateStoreOption func(s *StateStore) error
+
+// StateStore keeps the history of the state vectors of successive States responses per aircraft,
+// bounded by the number of samples and their age, to answer the point-in-time queries.
+// Use NewStateStore to create a new one, it's safe for concurrent use.
+type StateStore struct {
+	maxSamples int
+	maxAge     time.Duration
+
+	mu       sync.RWMutex
+	latest   int64
+	aircraft map[string]*stateRing
+}
+
+// WithStoreMaxSamples sets the maximum number of state vectors kept per aircraft, the default is 360.
+func WithStoreMaxSamples(maxSamples int) StateStoreOption {
+	return func(s *StateStore) error {
+		if maxSamples < 1 {
+			return fmt.Errorf("%w: %d samples", errInvalidStoreLimit, maxSamples)
+		}
+
+		s.maxSamples = maxSamples
+
+		return nil
+	}
+}
+
+// WithStoreMaxAge sets the maximum age of the state vectors kept, relative to the time of the most recent
+// ingested States, the default is one hour.
+func WithStoreMaxAge(maxAge time.Duration) StateStoreOption {
+	return func(s *StateStore) error {
+		if maxAge <= 0 {
+			return fmt.Errorf("%w: %s", errInvalidStoreLimit, maxAge)
+		}
+
+		s.maxAge = maxAge
+
+		return nil
+	}
+}
+
+// NewStateStore returns a new empty state store.
+func NewStateStore(opts ...StateStoreOption) (*StateStore, error) {
+	store := StateStore{
+		maxSamples: defaultStoreMaxSamples,
+		maxAge:     defaultStoreMaxAge,
+		aircraft:   make(map[string]*stateRing),
+	}
+
+	for _, opt := range opts {
+		if err := opt(&store); err != nil {
+			return nil, fmt.Errorf("state store: %w", err)
+		}
+	}
+
+	return &store, nil
+}
+
+// Ingest adds the state vectors of the states to the aircraft histories and drops the expired ones.
+// A state vector is associated with its position time, or its last contact time if the position is unknown.
+// The state vectors not more recent than the last one of the aircraft (e.g. the same position report
+// returned by two polls) are ignored.
+func (s *StateStore) Ingest(states *States) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stvec := range states.States {
+		ring, found := s.aircraft[stvec.Icao24]
+		if !found {
+			ring = newStateRing(s.maxSamples)
+			s.aircraft[stvec.Icao24] = ring
+		}
+
+		ring.push(stvec)
+
+		s.latest = max(s.latest, sampleTime(&stvec))
+	}
+
+	s.latest = max(s.latest, states.Time)
+	oldest := s.latest - int64(s.maxAge/time.Second)
+
+	for icao24, ring := range s.aircraft {
+		ring.dropBefore(oldest)
+
+		if ring.count == 0 {
+			delete(s.aircraft, icao24)
+		}
+	}
+}
+
+// Aircraft returns the ICAO24 addresses of the aircraft with history, sorted.
+func (s *StateStore) Aircraft() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	aircraft := make([]string, 0, len(s.aircraft))
+	for icao24 := range s.aircraft {
+		aircraft = append(aircraft, icao24)
+	}
+
+	slices.Sort(aircraft)
+
+	return aircraft
+}
+
+// History returns the state vectors of an aircraft in chronological order.
+func (s *StateStore) History(icao24 string) []StateVector {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ring, found := s.aircraft[strings.ToLower(icao24)]
+	if !found {
+		return nil
+	}
+
+	return ring.samples()
+}
+
+// StateAt returns the state vector of an aircraft at the given time in seconds since epoch (Unix time).
+// Between two samples the position is interpolated along the great-circle, the barometric and geometric
+// altitudes linearly and the true track along the shortest turn. The other fields are the ones of the
+// nearest sample. It returns false if the time is out of the aircraft history.
+func (s *StateStore) StateAt(icao24 string, time int64) (StateVector, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ring, found := s.aircraft[strings.ToLower(icao24)]
+	if !found {
+		return StateVector{}, false
+	}
+
+	samples := ring.samples()
+
+	index := sort.Search(len(samples), func(i int) bool {
+		return sampleTime(&samples[i]) >= time
+	})
+
+	switch {
+	case index == len(samples):
+		return StateVector{}, false
+	case sampleTime(&samples[index]) == time:
+		return samples[index], true
+	case index == 0:
+		return StateVector{}, false
+	}
+
+	return interpolateState(&samples[index-1], &samples[index], time), true
+}
+
+// Track rebuilds the flight track of an aircraft from its history.
+// The callsign is the last known one. It returns false if the aircraft has no history.
+func (s *StateStore) Track(icao24 string) (FlightTrack, bool) {
+	samples := s.History(icao24)
+	if len(samples) == 0 {
+		return FlightTrack{}, false
+	}
+
+	track := FlightTrack{
+		Icao24:    samples[0].Icao24,
+		StartTime: sampleTime(&samples[0]),
+		EndTime:   sampleTime(&samples[len(samples)-1]),
+		Path:      make([]WayPoint, 0, len(samples)),
+	}
+
+	for index := range samples {
+		stvec := &samples[index]
+
+		if stvec.Callsign != nil {
+			track.Callsign = stvec.Callsign
+		}
+
+		track.Path = append(track.Path, WayPoint{
+			Time:         sampleTime(stvec),
+			Latitude:     stvec.Latitude,
+			Longitude:    stvec.Longitude,
+			BaroAltitude: stvec.BaroAltitude,
+			TrueTrack:    stvec.TrueTrack,
+			OnGround:     stvec.OnGround,
+		})
+	}
+
+	return track, true
+}
+
+// stateRing is a ring buffer of the state vectors of an aircraft in chronological order.
+// The buffer grows up to its size, then the oldest state vectors are overwritten.
+type stateRing struct {
+	buffer []StateVector
+	size   int
+	start  int
+	count  int
+}
+
+func newStateRing(size int) *stateRing {
+	return &stateRing{size: size}
+}
+
+// push appends the state vector if it's more recent than the last one, overwriting the oldest if full.
+func (r *stateRing) push(stvec StateVector) {
+	if r.count > 0 && sampleTime(&stvec) <= sampleTime(r.at(r.count-1)) {
+		return
+	}
+
+	if r.count == r.size {
+		r.buffer[r.start] = stvec
+		r.start = (r.start + 1) % len(r.buffer)
+
+		return
+	}
+
+	if r.count == len(r.buffer) {
+		r.grow()
+	}
+
+	r.buffer[(r.start+r.count)%len(r.buffer)] = stvec
+	r.count++
+}
+
+// grow adds a slot to the full buffer, doubling its capacity up to the ring size when needed.
+func (r *stateRing) grow() {
+	if r.start == 0 && len(r.buffer) < cap(r.buffer) {
+		r.buffer = r.buffer[:len(r.buffer)+1]
+
+		return
+	}
+
+	buffer := make([]StateVector, len(r.buffer)+1, min(max(2*cap(r.buffer), minStateRingCapacity), r.size))
+
+	copied := copy(buffer, r.buffer[r.start:])
+	copy(buffer[copied:], r.buffer[:r.start])
+
+	r.buffer = buffer
+	r.start = 0
+}
+
+// dropBefore removes the state vectors older than the given time.
+func (r *stateRing) dropBefore(time int64) {
+	for r.count > 0 && sampleTime(r.at(0)) < time {
+		r.buffer[r.start] = StateVector{}
+		r.start = (r.start + 1) % len(r.buffer)
+		r.count--
+	}
+}
+
+func (r *stateRing) at(index int) *StateVector {
+	return &r.buffer[(r.start+index)%len(r.buffer)]
+}
+
+func (r *stateRing) samples() []StateVector {
+	samples := make([]StateVector, r.count)
+	for index := range samples {
+		samples[index] = *r.at(index)
+	}
+
+	return samples
+}
+
+// sampleTime returns the position time of the state vector, or its last contact time if unknown.
+func sampleTime(stvec *StateVector) int64 {
+	if stvec.TimePosition != nil {
+		return *stvec.TimePosition
+	}
+
+	return stvec.LastContact
+}
+
+// interpolateState returns the state vector at the time between the before and after samples.
+func interpolateState(before *StateVector, after *StateVector, time int64) StateVector {
+	startTime, endTime := sampleTime(before), sampleTime(after)
+	fraction := float64(time-startTime) / float64(endTime-startTime)
+
+	stvec := *before
+	if fraction >= 0.5 { //nolint:mnd
+		stvec = *after
+	}
+
+	stvec.TimePosition = &time
+	stvec.BaroAltitude = interpolateLinear(before.BaroAltitude, after.BaroAltitude, fraction)
+	stvec.GeoAltitude = interpolateLinear(before.GeoAltitude, after.GeoAltitude, fraction)
+	stvec.TrueTrack = interpolateTrack(before.TrueTrack, after.TrueTrack, fraction)
+	stvec.Latitude, stvec.Longitude = nil, nil
+
+	if before.Latitude != nil && before.Longitude != nil && after.Latitude != nil && after.Longitude != nil {
+		lat1, lon1, lat2, lon2 := *before.Latitude, *before.Longitude, *after.Latitude, *after.Longitude
+
+		distance := geo.Distance(lat1, lon1, lat2, lon2)
+		lat, lon := geo.Destination(lat1, lon1, geo.Bearing(lat1, lon1, lat2, lon2), distance*fraction)
+
+		stvec.Latitude, stvec.Longitude = &lat, &lon
+	}
+
+	return stvec
+}
+
+func interpolateLinear(before *float64, after *float64, fraction float64) *float64 {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	value := *before + (*after-*before)*fraction
+
+	return &value
+}
+
+// interpolateTrack interpolates the true track in decimal degrees along the shortest turn.
+func interpolateTrack(before *float64, after *float64, fraction float64) *float64 {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	const fullCircle, halfCircle = 360.0, 180.0
+
+	turn := math.Mod(*after-*before+fullCircle+halfCircle, fullCircle) - halfCircle
+	value := math.Mod(*before+turn*fraction+fullCircle, fullCircle)
+
+	return &value
+}
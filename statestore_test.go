@@ -0,0 +1,175 @@
+package gopensky_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+	"github.com/navidys/gopensky/geo"
+)
+
+func storeSample(icao24 string, timePosition int64, lat float64, lon float64, altitude float64, track float64,
+) gopensky.StateVector {
+	return gopensky.StateVector{
+		Icao24:       icao24,
+		TimePosition: &timePosition,
+		LastContact:  timePosition,
+		Latitude:     floatPtr(lat),
+		Longitude:    floatPtr(lon),
+		BaroAltitude: floatPtr(altitude),
+		TrueTrack:    floatPtr(track),
+	}
+}
+
+var _ = Describe("StateStore", func() {
+	It("interpolates the states between samples", func() {
+		store, err := gopensky.NewStateStore()
+		Expect(err).NotTo(HaveOccurred())
+
+		callsign := "DLH4AB  "
+		first := storeSample("3c6444", 1000, 50.0, 8.0, 1000, 350)
+		first.Callsign = &callsign
+
+		store.Ingest(&gopensky.States{Time: 1000, States: []gopensky.StateVector{first}})
+		store.Ingest(&gopensky.States{Time: 1010, States: []gopensky.StateVector{
+			storeSample("3c6444", 1000, 50.0, 8.0, 1000, 350),
+			storeSample("aaaaa1", 1008, 40.0, -75.0, 3000, 90),
+		}})
+		store.Ingest(&gopensky.States{Time: 1020, States: []gopensky.StateVector{
+			storeSample("3c6444", 1020, 50.2, 8.0, 2000, 10),
+		}})
+
+		Expect(store.Aircraft()).To(Equal([]string{"3c6444", "aaaaa1"}))
+		Expect(store.History("3C6444")).To(HaveLen(2))
+
+		stvec, found := store.StateAt("3c6444", 1005)
+		Expect(found).To(BeTrue())
+		Expect(*stvec.TimePosition).To(Equal(int64(1005)))
+		Expect(*stvec.Latitude).To(BeNumerically("~", 50.05, 1e-6))
+		Expect(*stvec.Longitude).To(BeNumerically("~", 8.0, 1e-6))
+		Expect(*stvec.BaroAltitude).To(BeNumerically("~", 1250, 1e-6))
+		Expect(*stvec.TrueTrack).To(BeNumerically("~", 355, 1e-6))
+		Expect(stvec.Callsign).To(Equal(&callsign))
+
+		stvec, found = store.StateAt("3c6444", 1015)
+		Expect(found).To(BeTrue())
+		Expect(*stvec.TrueTrack).To(BeNumerically("~", 5, 1e-6))
+		Expect(stvec.Callsign).To(BeNil())
+
+		stvec, found = store.StateAt("3c6444", 1020)
+		Expect(found).To(BeTrue())
+		Expect(*stvec.Latitude).To(Equal(50.2))
+
+		_, found = store.StateAt("3c6444", 999)
+		Expect(found).To(BeFalse())
+
+		_, found = store.StateAt("3c6444", 1021)
+		Expect(found).To(BeFalse())
+
+		_, found = store.StateAt("bbbbb1", 1005)
+		Expect(found).To(BeFalse())
+	})
+
+	It("interpolates the positions across the antimeridian", func() {
+		store, err := gopensky.NewStateStore()
+		Expect(err).NotTo(HaveOccurred())
+
+		store.Ingest(&gopensky.States{Time: 1010, States: []gopensky.StateVector{
+			storeSample("aaaaa1", 1000, 60.0, 179.9, 10000, 90),
+		}})
+		store.Ingest(&gopensky.States{Time: 1020, States: []gopensky.StateVector{
+			storeSample("aaaaa1", 1010, 60.0, -179.9, 10000, 90),
+		}})
+
+		stvec, found := store.StateAt("aaaaa1", 1005)
+		Expect(found).To(BeTrue())
+		Expect(geo.Distance(*stvec.Latitude, *stvec.Longitude, 60.0, 180.0)).To(BeNumerically("<", 10))
+	})
+
+	It("bounds the history by size and age", func() {
+		store, err := gopensky.NewStateStore(gopensky.WithStoreMaxSamples(3), gopensky.WithStoreMaxAge(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+
+		for timePosition := int64(1000); timePosition < 1050; timePosition += 10 {
+			store.Ingest(&gopensky.States{Time: timePosition, States: []gopensky.StateVector{
+				storeSample("aaaaa1", timePosition, 40.0, -75.0, 3000, 90),
+			}})
+		}
+
+		store.Ingest(&gopensky.States{Time: 1050, States: []gopensky.StateVector{
+			storeSample("aaaaa2", 1050, 40.0, -75.0, 3000, 90),
+		}})
+
+		history := store.History("aaaaa1")
+		Expect(history).To(HaveLen(3))
+		Expect(*history[0].TimePosition).To(Equal(int64(1020)))
+		Expect(*history[2].TimePosition).To(Equal(int64(1040)))
+
+		store.Ingest(&gopensky.States{Time: 1085})
+		Expect(store.Aircraft()).To(Equal([]string{"aaaaa1", "aaaaa2"}))
+		Expect(store.History("aaaaa1")).To(HaveLen(2))
+
+		store.Ingest(&gopensky.States{Time: 1101})
+		Expect(store.Aircraft()).To(Equal([]string{"aaaaa2"}))
+	})
+
+	It("keeps the history in order while the buffers grow", func() {
+		for maxAge, wants := range map[time.Duration]int{time.Minute: 7, 5 * time.Minute: 20} {
+			store, err := gopensky.NewStateStore(gopensky.WithStoreMaxSamples(20), gopensky.WithStoreMaxAge(maxAge))
+			Expect(err).NotTo(HaveOccurred())
+
+			for timePosition := int64(1000); timePosition < 1300; timePosition += 10 {
+				store.Ingest(&gopensky.States{Time: timePosition, States: []gopensky.StateVector{
+					storeSample("aaaaa1", timePosition, 40.0, -75.0, 3000, 90),
+				}})
+
+				history := store.History("aaaaa1")
+				Expect(*history[len(history)-1].TimePosition).To(Equal(timePosition))
+
+				for index := 1; index < len(history); index++ {
+					Expect(*history[index].TimePosition).To(Equal(*history[index-1].TimePosition + 10))
+				}
+			}
+
+			Expect(store.History("aaaaa1")).To(HaveLen(wants))
+		}
+	})
+
+	It("rebuilds the flight track", func() {
+		store, err := gopensky.NewStateStore()
+		Expect(err).NotTo(HaveOccurred())
+
+		callsign := "BAW123  "
+		last := storeSample("aaaaa1", 1010, 51.5, -0.4, 0, 270)
+		last.Callsign = &callsign
+		last.OnGround = true
+
+		store.Ingest(&gopensky.States{Time: 1000, States: []gopensky.StateVector{
+			storeSample("aaaaa1", 1000, 51.4, -0.3, 300, 270),
+		}})
+		store.Ingest(&gopensky.States{Time: 1010, States: []gopensky.StateVector{last}})
+
+		track, found := store.Track("aaaaa1")
+		Expect(found).To(BeTrue())
+		Expect(track.Icao24).To(Equal("aaaaa1"))
+		Expect(track.StartTime).To(Equal(int64(1000)))
+		Expect(track.EndTime).To(Equal(int64(1010)))
+		Expect(*track.Callsign).To(Equal(callsign))
+		Expect(track.Path).To(HaveLen(2))
+		Expect(*track.Path[0].BaroAltitude).To(Equal(300.0))
+		Expect(track.Path[1].OnGround).To(BeTrue())
+
+		_, found = store.Track("aaaaa2")
+		Expect(found).To(BeFalse())
+	})
+
+	It("tests NewStateStore errors", func() {
+		_, err := gopensky.NewStateStore(gopensky.WithStoreMaxSamples(0))
+		Expect(err.Error()).To(Equal("state store: invalid state store limit: 0 samples"))
+
+		_, err = gopensky.NewStateStore(gopensky.WithStoreMaxAge(-time.Second))
+		Expect(err.Error()).To(Equal("state store: invalid state store limit: -1s"))
+	})
+})
@@ -0,0 +1,344 @@
+package gopensky
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+)
+
+// errStreamStopped is returned by the stream decode functions when the consumer stops the iteration.
+var errStreamStopped = errors.New("stream stopped")
+
+// StreamStates retrieves state vectors for a given time and yields them as they are decoded from the response.
+// If time = 0 the most recent ones are taken. See GetStates for the parameters.
+// A bounding box crossing the antimeridian is streamed as two regions, an aircraft returned by both
+// is yielded once (the first decoded state vector, unlike GetStatesInRegions which keeps the most recent contact).
+// The iteration stops after the first error. The streaming calls bypass the connection cache.
+func StreamStates(ctx context.Context, time int64, icao24 []string,
+	bBox *BoundingBoxOptions, extended bool,
+) iter.Seq2[StateVector, error] {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return func(yield func(StateVector, error) bool) {
+			yield(StateVector{}, err)
+		}
+	}
+
+	return client.StreamStates(ctx, time, icao24, bBox, extended)
+}
+
+// StreamStates retrieves state vectors for a given time and yields them as they are decoded from the response.
+// If time = 0 the most recent ones are taken. See GetStates for the parameters.
+// A bounding box crossing the antimeridian is streamed as two regions, an aircraft returned by both
+// is yielded once (the first decoded state vector, unlike GetStatesInRegions which keeps the most recent contact).
+// The iteration stops after the first error. The streaming calls bypass the connection cache.
+func (c *Client) StreamStates(ctx context.Context, time int64, icao24 []string,
+	bBox *BoundingBoxOptions, extended bool,
+) iter.Seq2[StateVector, error] {
+	return func(yield func(StateVector, error) bool) {
+		if time < 0 {
+			yield(StateVector{}, ErrInvalidUnixTime)
+
+			return
+		}
+
+		icao24, err := normalizeICAO24(icao24)
+		if err != nil {
+			yield(StateVector{}, err)
+
+			return
+		}
+
+		regions := []BoundingBoxOptions{{}}
+
+		if bBox != nil {
+			if err := bBox.Validate(); err != nil {
+				yield(StateVector{}, err)
+
+				return
+			}
+
+			regions = bBox.Split()
+		}
+
+		if len(regions) > 1 {
+			yield = yieldUniqueStates(yield)
+		}
+
+		for index := range regions {
+			var region *BoundingBoxOptions
+			if bBox != nil {
+				region = &regions[index]
+			}
+
+			stopped, err := c.streamStates(ctx, getStateRequestParams(time, icao24, region, extended), yield)
+			if err != nil {
+				yield(StateVector{}, err)
+
+				return
+			}
+
+			if stopped {
+				return
+			}
+		}
+	}
+}
+
+// yieldUniqueStates wraps the yield function to skip the state vectors of the already yielded aircraft.
+func yieldUniqueStates(yield func(StateVector, error) bool) func(StateVector, error) bool {
+	seen := make(map[string]struct{})
+
+	return func(stvec StateVector, err error) bool {
+		if err == nil {
+			if _, found := seen[stvec.Icao24]; found {
+				return true
+			}
+
+			seen[stvec.Icao24] = struct{}{}
+		}
+
+		return yield(stvec, err)
+	}
+}
+
+// streamStates streams the state vectors of a /states/all request,
+// it returns true if the consumer stopped the iteration.
+func (c *Client) streamStates(ctx context.Context, requestParams url.Values, yield func(StateVector, error) bool,
+) (bool, error) {
+	ctx, call := c.conn.startCall(ctx, "StreamStates", "/states/all", requestParams)
+
+	count := 0
+	stopped := false
+
+	err := c.conn.streamJSON(ctx, call, func(decoder *json.Decoder) error {
+		err := decodeStatesStream(decoder, func(stvec StateVector) error {
+			count++
+
+			if !yield(stvec, nil) {
+				stopped = true
+
+				return errStreamStopped
+			}
+
+			return nil
+		})
+
+		var stvecErr *StateVectorError
+		if errors.As(err, &stvecErr) {
+			c.conn.logStateVectorError(ctx, "/states/all", requestParams, stvecErr)
+
+			return fmt.Errorf("decode state vector: %w", stvecErr)
+		}
+
+		return err
+	})
+
+	call.end(count, err)
+
+	return stopped, err
+}
+
+// StreamFlightsByInterval retrieves flights for a certain time interval [begin, end]
+// and yields them as they are decoded from the response.
+// The given time interval must not be larger than two hours!
+// The iteration stops after the first error. The streaming calls bypass the connection cache.
+func StreamFlightsByInterval(ctx context.Context, begin int64, end int64) iter.Seq2[FlighData, error] {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return func(yield func(FlighData, error) bool) {
+			yield(FlighData{}, err)
+		}
+	}
+
+	return client.StreamFlightsByInterval(ctx, begin, end)
+}
+
+// StreamFlightsByInterval retrieves flights for a certain time interval [begin, end]
+// and yields them as they are decoded from the response.
+// The given time interval must not be larger than two hours!
+// The iteration stops after the first error. The streaming calls bypass the connection cache.
+func (c *Client) StreamFlightsByInterval(ctx context.Context, begin int64, end int64) iter.Seq2[FlighData, error] {
+	return func(yield func(FlighData, error) bool) {
+		if begin <= 0 || end <= 0 {
+			yield(FlighData{}, ErrInvalidUnixTime)
+
+			return
+		}
+
+		requestParams := getFlightsRequestParams("", "", begin, end)
+
+		ctx, call := c.conn.startCall(ctx, "StreamFlightsByInterval", "/flights/all", requestParams)
+
+		count := 0
+
+		err := c.conn.streamJSON(ctx, call, func(decoder *json.Decoder) error {
+			return decodeArrayStream(decoder, func(flight FlighData) bool {
+				count++
+
+				return yield(flight, nil)
+			})
+		})
+
+		call.end(count, err)
+
+		if err != nil {
+			yield(FlighData{}, err)
+		}
+	}
+}
+
+// streamJSON sends the call request and passes the successful response body decoder to decodeFunc.
+// It returns nil if the consumer stopped the iteration.
+func (c *Connection) streamJSON(ctx context.Context, call *apiCall, decodeFunc func(*json.Decoder) error) error {
+	response, err := c.doGetRequest(ctx, call.info.Endpoint, call.info.Params)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	defer response.Body.Close() //nolint:errcheck
+
+	call.result.StatusCode = response.StatusCode
+
+	if !response.isSuccess() {
+		data, err := response.readBody()
+		if err != nil {
+			return err
+		}
+
+		call.result.ResponseSize = int64(len(data))
+
+		return response.processData(data, nil)
+	}
+
+	body := &countingReader{reader: response.Body}
+
+	err = decodeFunc(json.NewDecoder(body))
+
+	call.result.ResponseSize = body.count
+
+	if errors.Is(err, errStreamStopped) {
+		return nil
+	}
+
+	return err
+}
+
+// decodeStatesStream decodes the states response object and calls rowFunc for each state vector,
+// the decoding stops at the first rowFunc error.
+func decodeStatesStream(decoder *json.Decoder, rowFunc func(StateVector) error) error {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("decode states: %w", err)
+		}
+
+		if key != "states" {
+			var value json.RawMessage
+
+			if err := decoder.Decode(&value); err != nil {
+				return fmt.Errorf("decode states %v: %w", key, err)
+			}
+
+			continue
+		}
+
+		if err := decodeStatesRows(decoder, rowFunc); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+func decodeStatesRows(decoder *json.Decoder, rowFunc func(StateVector) error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("decode states: %w", err)
+	}
+
+	// states is null if there is no state vector.
+	if token == nil {
+		return nil
+	}
+
+	if token != json.Delim('[') {
+		return fmt.Errorf("%w: %v", errUnexpectedJSONToken, token)
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var row stateVectorRow
+
+		if err := decoder.Decode(&row); err != nil {
+			var stvecErr *StateVectorError
+			if errors.As(err, &stvecErr) {
+				stvecErr.Row = index
+
+				return stvecErr
+			}
+
+			return fmt.Errorf("decode state vector %d: %w", index, err)
+		}
+
+		if err := rowFunc(StateVector(row)); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, ']')
+}
+
+// decodeArrayStream decodes a JSON array and calls yield for each item.
+func decodeArrayStream[T any](decoder *json.Decoder, yield func(T) bool) error {
+	if err := expectDelim(decoder, '['); err != nil {
+		return err
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var item T
+
+		if err := decoder.Decode(&item); err != nil {
+			return fmt.Errorf("decode item %d: %w", index, err)
+		}
+
+		if !yield(item) {
+			return errStreamStopped
+		}
+	}
+
+	return expectDelim(decoder, ']')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("decode %v: %w", delim, err)
+	}
+
+	if token != delim {
+		return fmt.Errorf("%w: %v, expected %v", errUnexpectedJSONToken, token, delim)
+	}
+
+	return nil
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+
+	return n, err //nolint:wrapcheck
+}
@@ -0,0 +1,140 @@
+package gopensky_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+var _ = Describe("Stream", func() {
+	var (
+		server   *httptest.Server
+		dataFile string
+		client   *gopensky.Client
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if dataFile == "" {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			data, err := os.ReadFile(dataFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			w.Write(data)
+		}))
+
+		var err error
+
+		client, err = gopensky.NewClient("", "", gopensky.WithBaseURL(server.URL))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("StreamStates", func() {
+		It("yields the same state vectors as GetStates", func() {
+			dataFile = "mock_data/all_states.json"
+
+			states, err := client.GetStates(context.Background(), 0, nil, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			streamed := make([]gopensky.StateVector, 0)
+
+			for stvec, err := range client.StreamStates(context.Background(), 0, nil, nil, false) {
+				Expect(err).NotTo(HaveOccurred())
+
+				streamed = append(streamed, stvec)
+			}
+
+			Expect(streamed).To(Equal(states.States))
+		})
+
+		It("stops when the consumer breaks", func() {
+			dataFile = "mock_data/all_states.json"
+
+			count := 0
+
+			for _, err := range client.StreamStates(context.Background(), 0, nil, nil, false) {
+				Expect(err).NotTo(HaveOccurred())
+
+				count++
+
+				if count == 2 {
+					break
+				}
+			}
+
+			Expect(count).To(Equal(2))
+		})
+
+		It("yields the decode errors", func() {
+			dataFile = "mock_data/errors/states05.json"
+
+			var errs []error
+
+			for _, err := range client.StreamStates(context.Background(), 0, nil, nil, false) {
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Error()).To(HavePrefix("decode state vector:"))
+		})
+
+		It("yields the invalid parameters and connection errors", func() {
+			for _, err := range client.StreamStates(context.Background(), -1, nil, nil, false) {
+				Expect(err).To(MatchError(gopensky.ErrInvalidUnixTime))
+			}
+
+			for _, err := range gopensky.StreamStates(context.Background(), 0, nil, nil, false) {
+				Expect(err.Error()).To(ContainSubstring("invalid context key"))
+			}
+		})
+	})
+
+	Describe("StreamFlightsByInterval", func() {
+		It("yields the flights", func() {
+			dataFile = "mock_data/flights_data.json"
+
+			ctx, err := gopensky.NewConnection(context.Background(), "", "", gopensky.WithBaseURL(server.URL))
+			Expect(err).NotTo(HaveOccurred())
+
+			var icao24 []string
+
+			for flight, err := range gopensky.StreamFlightsByInterval(ctx, 1689193000, 1689197900) {
+				Expect(err).NotTo(HaveOccurred())
+
+				icao24 = append(icao24, flight.Icao24)
+			}
+
+			Expect(icao24).To(HaveLen(3))
+			Expect(icao24[0]).To(Equal("c060b9"))
+		})
+
+		It("yields the API errors", func() {
+			dataFile = ""
+
+			var errs []error
+
+			for _, err := range client.StreamFlightsByInterval(context.Background(), 1689193000, 1689197900) {
+				errs = append(errs, err)
+			}
+
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(MatchError(gopensky.ErrNotFound))
+		})
+	})
+})
@@ -3,6 +3,7 @@ package gopensky
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/url"
 	"time"
 )
@@ -63,6 +64,16 @@ type WayPoint struct {
 
 // GetTrackByAircraft retrieves the trajectory for a certain aircraft at a given time.
 func GetTrackByAircraft(ctx context.Context, icao24 string, time int64) (FlightTrack, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return FlightTrack{}, err
+	}
+
+	return client.GetTrackByAircraft(ctx, icao24, time)
+}
+
+// GetTrackByAircraft retrieves the trajectory for a certain aircraft at a given time.
+func (c *Client) GetTrackByAircraft(ctx context.Context, icao24 string, time int64) (FlightTrack, error) {
 	var (
 		flightTrack         FlightTrack
 		flightTrackResponse FlightTrackResponse
@@ -72,35 +83,44 @@ func GetTrackByAircraft(ctx context.Context, icao24 string, time int64) (FlightT
 		return flightTrack, ErrInvalidAircraftName
 	}
 
-	if time < 0 {
-		return flightTrack, ErrInvalidUnixTime
+	address, err := ParseICAO24(icao24)
+	if err != nil {
+		return flightTrack, err
 	}
 
-	conn, err := getClient(ctx)
-	if err != nil {
-		return flightTrack, fmt.Errorf("client: %w", err)
+	icao24 = address.String()
+
+	if time < 0 {
+		return flightTrack, ErrInvalidUnixTime
 	}
 
 	requestParams := getTracksRequestParams(time, icao24)
 
-	response, err := conn.doGetRequest(ctx, "/tracks/all", requestParams)
+	ctx, call := c.conn.startCall(ctx, "GetTrackByAircraft", "/tracks/all", requestParams)
+
+	err = c.conn.getJSON(ctx, call, &flightTrackResponse)
 	if err != nil {
-		return flightTrack, fmt.Errorf("do request: %w", err)
+		call.end(0, err)
+
+		return flightTrack, err
 	}
 
-	errRespProcess := response.process(&flightTrackResponse)
+	err = call.decode(func() error {
+		var err error
 
-	err = response.Body.Close()
-	if err != nil {
-		return flightTrack, fmt.Errorf("response body close %w", err)
-	}
+		flightTrack, err = parseFlightTrackResponse(&flightTrackResponse)
 
-	if errRespProcess != nil {
-		return flightTrack, errRespProcess
-	}
+		return err
+	})
+
+	call.end(len(flightTrack.Path), err)
 
-	flightTrack, err = parseFlightTrackResponse(&flightTrackResponse)
 	if err != nil {
+		c.conn.logger.LogAttrs(ctx, slog.LevelError, "parse flight track failed",
+			slog.String("endpoint", "/tracks/all"),
+			slog.String("params", requestParams.Encode()),
+			slog.Any("error", err))
+
 		return flightTrack, fmt.Errorf("parse track: %w", err)
 	}
 
@@ -122,10 +142,10 @@ func parseFlightTrackResponse(response *FlightTrackResponse) (FlightTrack, error
 		flightTrack.StartTime = startTime
 	}
 
-	for _, waypointData := range response.Path {
+	for index, waypointData := range response.Path {
 		waypoint, err := decodeWaypoint(waypointData)
 		if err != nil {
-			return flightTrack, fmt.Errorf("decode waypoint: %w", err)
+			return flightTrack, fmt.Errorf("decode waypoint %d: %w", index, err)
 		}
 
 		flightTrack.Path = append(flightTrack.Path, *waypoint)
@@ -135,7 +155,7 @@ func parseFlightTrackResponse(response *FlightTrackResponse) (FlightTrack, error
 }
 
 func decodeWaypoint(data []any) (*WayPoint, error) { //nolint:funlen,cyclop
-	if len(data) < trackOnGroundIndex {
+	if len(data) <= trackOnGroundIndex {
 		return nil, errWaypointsDataCount
 	}
 
@@ -143,12 +163,15 @@ func decodeWaypoint(data []any) (*WayPoint, error) { //nolint:funlen,cyclop
 
 	// Time index
 	if data[trackTimeIndex] != nil {
-		wtime, assertionOK := data[trackTimeIndex].(int64)
-		if !assertionOK {
+		// the decoded JSON numbers are float64.
+		switch wtime := data[trackTimeIndex].(type) {
+		case float64:
+			waypoint.Time = int64(wtime)
+		case int64:
+			waypoint.Time = wtime
+		default:
 			return nil, fmt.Errorf("%w: %v", errWaypointTime, data[trackTimeIndex])
 		}
-
-		waypoint.Time = wtime
 	}
 
 	// Latitude index
@@ -0,0 +1,73 @@
+/*
+Package units converts the OpenSky SI measurements (meters, m/s) to the aviation units.
+
+The state vector measurements are pointers which are nil when unknown, use Convert for nil-safe conversions:
+
+	altitude := units.Convert(stvec.BaroAltitude, units.Feet)
+	if altitude != nil {
+		fmt.Printf("%.0f ft\n", *altitude)
+	}
+*/
+package units
+
+const (
+	// MetersPerFoot is the length of an international foot in meters.
+	MetersPerFoot = 0.3048
+
+	// MetersPerNauticalMile is the length of a nautical mile in meters.
+	MetersPerNauticalMile = 1852.0
+
+	secondsPerMinute = 60
+	secondsPerHour   = 3600
+	metersPerKm      = 1000
+)
+
+// Feet converts an altitude from meters to feet.
+func Feet(meters float64) float64 {
+	return meters / MetersPerFoot
+}
+
+// FlightLevel converts a barometric altitude from meters to flight level (hundreds of feet).
+func FlightLevel(meters float64) float64 {
+	return Feet(meters) / 100 //nolint:mnd
+}
+
+// Knots converts a speed from meters per second to knots (nautical miles per hour).
+func Knots(metersPerSecond float64) float64 {
+	return metersPerSecond * secondsPerHour / MetersPerNauticalMile
+}
+
+// KilometersPerHour converts a speed from meters per second to kilometers per hour.
+func KilometersPerHour(metersPerSecond float64) float64 {
+	return metersPerSecond * secondsPerHour / metersPerKm
+}
+
+// FeetPerMinute converts a vertical rate from meters per second to feet per minute.
+func FeetPerMinute(metersPerSecond float64) float64 {
+	return Feet(metersPerSecond) * secondsPerMinute
+}
+
+// NauticalMiles converts a distance from meters to nautical miles.
+func NauticalMiles(meters float64) float64 {
+	return meters / MetersPerNauticalMile
+}
+
+// Convert returns the converted value, or nil if the value is nil.
+func Convert(value *float64, conversion func(float64) float64) *float64 {
+	if value == nil {
+		return nil
+	}
+
+	converted := conversion(*value)
+
+	return &converted
+}
+
+// ValueOr returns the value, or the fallback value if the value is nil.
+func ValueOr(value *float64, fallback float64) float64 {
+	if value == nil {
+		return fallback
+	}
+
+	return *value
+}
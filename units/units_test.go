@@ -0,0 +1,35 @@
+package units_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky/units"
+)
+
+var _ = Describe("Units", func() {
+	It("converts the altitudes", func() {
+		Expect(units.Feet(11277.6)).To(BeNumerically("~", 37000, 0.001))
+		Expect(units.FlightLevel(11277.6)).To(BeNumerically("~", 370, 0.001))
+	})
+
+	It("converts the speeds", func() {
+		Expect(units.Knots(231.5)).To(BeNumerically("~", 450, 0.01))
+		Expect(units.KilometersPerHour(100)).To(BeNumerically("~", 360, 0.001))
+		Expect(units.FeetPerMinute(-5.08)).To(BeNumerically("~", -1000, 0.001))
+	})
+
+	It("converts the distances", func() {
+		Expect(units.NauticalMiles(1852)).To(BeNumerically("~", 1, 0.001))
+	})
+
+	It("converts the nil values", func() {
+		altitude := 304.8
+
+		Expect(*units.Convert(&altitude, units.Feet)).To(BeNumerically("~", 1000, 0.001))
+		Expect(units.Convert(nil, units.Feet)).To(BeNil())
+
+		Expect(units.ValueOr(&altitude, 0)).To(Equal(304.8))
+		Expect(units.ValueOr(nil, -1)).To(Equal(float64(-1)))
+	})
+})
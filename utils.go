@@ -1,7 +1,74 @@
 package gopensky
 
-import "fmt"
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sync"
+)
 
 func floatToString(data float64) string {
 	return fmt.Sprintf("%f", data)
 }
+
+// readCSVRecords reads the records of an embedded CSV dataset, without the header line.
+func readCSVRecords(data []byte) ([][]string, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	return records[1:], nil
+}
+
+// fetchAll calls fetch for the [0, count) indexes with at most workers concurrent calls,
+// and returns the results in the indexes order. The first error cancels the pending calls.
+func fetchAll[T any](ctx context.Context, count int, workers int,
+	fetch func(ctx context.Context, index int) (T, error),
+) ([]T, error) {
+	results := make([]T, count)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+
+	semaphore := make(chan struct{}, workers)
+
+	for index := range count {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Go(func() {
+			defer func() { <-semaphore }()
+
+			result, err := fetch(ctx, index)
+			if err != nil {
+				cancel(err)
+
+				return
+			}
+
+			results[index] = result
+		})
+	}
+
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return results, nil
+}
@@ -0,0 +1,429 @@
+package gopensky
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchInterval   = 10 * time.Second
+	defaultWatchLostAfter  = 60 * time.Second
+	defaultWatchMaxBackoff = 5 * time.Minute
+)
+
+// Clock is the time source of the Watcher, it can be replaced by a fake clock in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel which receives the current time after the duration.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WatcherEventType is the type of a watcher event.
+type WatcherEventType int
+
+const (
+	EventAppeared        WatcherEventType = iota // Aircraft appeared in the states
+	EventPositionUpdated                         // Position or altitude updated
+	EventCallsignChanged                         // Callsign changed
+	EventSquawkChanged                           // Squawk changed
+	EventTookOff                                 // Aircraft is no longer on ground
+	EventLanded                                  // Aircraft is on ground
+	EventLostContact                             // Aircraft is no longer in the states
+)
+
+var watcherEventTypeNames = [...]string{ //nolint:gochecknoglobals
+	EventAppeared:        "appeared",
+	EventPositionUpdated: "position-updated",
+	EventCallsignChanged: "callsign-changed",
+	EventSquawkChanged:   "squawk-changed",
+	EventTookOff:         "took-off",
+	EventLanded:          "landed",
+	EventLostContact:     "lost-contact",
+}
+
+// String returns the event type name (e.g. appeared, landed).
+func (t WatcherEventType) String() string {
+	if t >= 0 && int(t) < len(watcherEventTypeNames) {
+		return watcherEventTypeNames[t]
+	}
+
+	return "WatcherEventType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// WatcherEvent is a change of the traffic between two polls.
+type WatcherEvent struct {
+	// Type of the event.
+	Type WatcherEventType
+
+	// Time of the states the event was detected in, in seconds since epoch (Unix time).
+	Time int64
+
+	// Current state vector of the aircraft, the last known one for EventLostContact.
+	State StateVector
+
+	// Previous state vector of the aircraft, nil for EventAppeared and EventLostContact.
+	Previous *StateVector
+}
+
+// WatcherOption is a configuration option of the Watcher.
+type WatcherOption func(w *Watcher) error
+
+// Watcher polls the state vectors at a regular interval, keeps the picture of the traffic in memory
+// and emits the changes as events. Use NewWatcher to create a new one.
+type Watcher struct {
+	client     *Client
+	clock      Clock
+	interval   time.Duration
+	lostAfter  time.Duration
+	maxBackoff time.Duration
+	icao24     []string
+	bBox       *BoundingBoxOptions
+	extended   bool
+	onError    func(err error)
+
+	mu       sync.Mutex
+	aircraft map[string]StateVector
+	lastSeen map[string]int64
+}
+
+// WithWatchInterval sets the polling interval, the default is 10 seconds.
+func WithWatchInterval(interval time.Duration) WatcherOption {
+	return func(w *Watcher) error {
+		if interval <= 0 {
+			return fmt.Errorf("%w: %s", errInvalidWatchDuration, interval)
+		}
+
+		w.interval = interval
+
+		return nil
+	}
+}
+
+// WithWatchICAO24 watches only the given ICAO24 addresses.
+func WithWatchICAO24(icao24 ...string) WatcherOption {
+	return func(w *Watcher) error {
+		normalized, err := normalizeICAO24(icao24)
+		if err != nil {
+			return err
+		}
+
+		w.icao24 = normalized
+
+		return nil
+	}
+}
+
+// WithWatchBoundingBox watches only the aircraft within the bounding box.
+func WithWatchBoundingBox(bBox *BoundingBoxOptions) WatcherOption {
+	return func(w *Watcher) error {
+		if bBox != nil {
+			if err := bBox.Validate(); err != nil {
+				return err
+			}
+		}
+
+		w.bBox = bBox
+
+		return nil
+	}
+}
+
+// WithWatchExtended requests the category of aircraft.
+func WithWatchExtended() WatcherOption {
+	return func(w *Watcher) error {
+		w.extended = true
+
+		return nil
+	}
+}
+
+// WithWatchLostAfter sets how long an aircraft can be missing from the states before
+// EventLostContact is emitted, the default is 60 seconds.
+func WithWatchLostAfter(lostAfter time.Duration) WatcherOption {
+	return func(w *Watcher) error {
+		if lostAfter <= 0 {
+			return fmt.Errorf("%w: %s", errInvalidWatchDuration, lostAfter)
+		}
+
+		w.lostAfter = lostAfter
+
+		return nil
+	}
+}
+
+// WithWatchMaxBackoff sets the upper limit of the wait time after failed polls, the default is 5 minutes.
+// The wait time doubles after each consecutive failure, starting from twice the polling interval.
+func WithWatchMaxBackoff(maxBackoff time.Duration) WatcherOption {
+	return func(w *Watcher) error {
+		if maxBackoff <= 0 {
+			return fmt.Errorf("%w: %s", errInvalidWatchDuration, maxBackoff)
+		}
+
+		w.maxBackoff = maxBackoff
+
+		return nil
+	}
+}
+
+// WithWatchClock sets the clock of the watcher (e.g. a fake clock in tests).
+func WithWatchClock(clock Clock) WatcherOption {
+	return func(w *Watcher) error {
+		w.clock = clock
+
+		return nil
+	}
+}
+
+// WithWatchErrorHandler sets the function called when a poll fails.
+func WithWatchErrorHandler(handler func(err error)) WatcherOption {
+	return func(w *Watcher) error {
+		w.onError = handler
+
+		return nil
+	}
+}
+
+// NewWatcher returns a new states watcher using the connection of the context build by NewConnection.
+func NewWatcher(ctx context.Context, opts ...WatcherOption) (*Watcher, error) {
+	client, err := contextClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.NewWatcher(opts...)
+}
+
+// NewWatcher returns a new states watcher using the client connection.
+func (c *Client) NewWatcher(opts ...WatcherOption) (*Watcher, error) {
+	watcher := Watcher{
+		client:     c,
+		clock:      realClock{},
+		interval:   defaultWatchInterval,
+		lostAfter:  defaultWatchLostAfter,
+		maxBackoff: defaultWatchMaxBackoff,
+		aircraft:   make(map[string]StateVector),
+		lastSeen:   make(map[string]int64),
+	}
+
+	for _, opt := range opts {
+		if err := opt(&watcher); err != nil {
+			return nil, fmt.Errorf("watcher: %w", err)
+		}
+	}
+
+	return &watcher, nil
+}
+
+// Run polls the states until the context is done and calls the handler with the events of each poll.
+// The failed polls are retried with exponential backoff. The lost contacts are only detected by the
+// successful polls, so no EventLostContact is emitted while the polls are failing.
+// Run must not be called concurrently.
+func (w *Watcher) Run(ctx context.Context, handler func(event WatcherEvent)) {
+	failures := 0
+
+	for {
+		wait := w.interval
+
+		states, err := w.client.GetStates(ctx, 0, w.icao24, w.bBox, w.extended)
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			failures++
+			wait = w.backoff(failures)
+
+			w.client.conn.logger.LogAttrs(ctx, slog.LevelWarn, "watcher poll failed",
+				slog.String("error", err.Error()), slog.Int("failures", failures), slog.Duration("wait", wait))
+
+			if w.onError != nil {
+				w.onError(err)
+			}
+		default:
+			failures = 0
+
+			for _, event := range w.update(states) {
+				handler(event)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.clock.After(wait):
+		}
+	}
+}
+
+// Events runs the watcher in a new goroutine and returns the events channel,
+// which is closed when the context is done.
+func (w *Watcher) Events(ctx context.Context) <-chan WatcherEvent {
+	events := make(chan WatcherEvent)
+
+	go func() {
+		defer close(events)
+
+		w.Run(ctx, func(event WatcherEvent) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return events
+}
+
+// Aircraft returns the last known state vectors of the watched aircraft sorted by ICAO24 address.
+func (w *Watcher) Aircraft() []StateVector {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	aircraft := make([]StateVector, 0, len(w.aircraft))
+	for _, stvec := range w.aircraft {
+		aircraft = append(aircraft, stvec)
+	}
+
+	slices.SortFunc(aircraft, func(a, b StateVector) int {
+		return cmp.Compare(a.Icao24, b.Icao24)
+	})
+
+	return aircraft
+}
+
+// Lookup returns the last known state vector of a watched aircraft.
+func (w *Watcher) Lookup(icao24 string) (StateVector, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stvec, found := w.aircraft[strings.ToLower(icao24)]
+
+	return stvec, found
+}
+
+// backoff returns the wait time after the given number of consecutive failures.
+func (w *Watcher) backoff(failures int) time.Duration {
+	wait := w.interval
+
+	for range failures {
+		wait *= 2
+		if wait >= w.maxBackoff {
+			return w.maxBackoff
+		}
+	}
+
+	return wait
+}
+
+// update updates the traffic picture with the states and returns the changes.
+func (w *Watcher) update(states *States) []WatcherEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := states.Time
+	if now == 0 {
+		now = w.clock.Now().Unix()
+	}
+
+	var events []WatcherEvent
+
+	for _, stvec := range states.States {
+		previous, found := w.aircraft[stvec.Icao24]
+
+		w.aircraft[stvec.Icao24] = stvec
+		w.lastSeen[stvec.Icao24] = now
+
+		if !found {
+			events = append(events, WatcherEvent{Type: EventAppeared, Time: now, State: stvec})
+
+			continue
+		}
+
+		for _, eventType := range stateChanges(&previous, &stvec) {
+			events = append(events, WatcherEvent{Type: eventType, Time: now, State: stvec, Previous: &previous})
+		}
+	}
+
+	var lost []StateVector
+
+	for icao24, lastSeen := range w.lastSeen {
+		if time.Duration(now-lastSeen)*time.Second >= w.lostAfter {
+			lost = append(lost, w.aircraft[icao24])
+
+			delete(w.aircraft, icao24)
+			delete(w.lastSeen, icao24)
+		}
+	}
+
+	slices.SortFunc(lost, func(a, b StateVector) int {
+		return cmp.Compare(a.Icao24, b.Icao24)
+	})
+
+	for _, stvec := range lost {
+		events = append(events, WatcherEvent{Type: EventLostContact, Time: now, State: stvec})
+	}
+
+	return events
+}
+
+// stateChanges returns the events types of the changes between two state vectors of an aircraft.
+func stateChanges(previous *StateVector, current *StateVector) []WatcherEventType {
+	var changes []WatcherEventType
+
+	switch {
+	case previous.OnGround && !current.OnGround:
+		changes = append(changes, EventTookOff)
+	case !previous.OnGround && current.OnGround:
+		changes = append(changes, EventLanded)
+	}
+
+	if callsign := current.ParseCallsign().Value; callsign != "" && callsign != previous.ParseCallsign().Value {
+		changes = append(changes, EventCallsignChanged)
+	}
+
+	if optionalString(previous.Squawk) != optionalString(current.Squawk) {
+		changes = append(changes, EventSquawkChanged)
+	}
+
+	if !equalOptional(previous.Latitude, current.Latitude) || !equalOptional(previous.Longitude, current.Longitude) ||
+		!equalOptional(previous.BaroAltitude, current.BaroAltitude) {
+		changes = append(changes, EventPositionUpdated)
+	}
+
+	return changes
+}
+
+func optionalString(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func equalOptional[T comparable](a *T, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
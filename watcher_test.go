@@ -0,0 +1,217 @@
+package gopensky_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/h2non/gock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/navidys/gopensky"
+)
+
+const (
+	watchStates1 = `{"time": 100, "states": [
+		["aaaaa1", "DLH1    ", "Germany", 100, 100, 8.5, 50.0, null, true, 0.0,
+		 90.0, 0.0, null, null, "1000", false, 0],
+		["aaaaa2", "BAW2    ", "United Kingdom", 100, 100, -0.4, 51.4, 1000.0, false, 200.0,
+		 90.0, 0.0, null, 1100.0, "2000", false, 0]]}`
+	watchStates2 = `{"time": 110, "states": [
+		["aaaaa1", "DLH1    ", "Germany", 110, 110, 8.6, 50.1, 300.0, false, 80.0,
+		 90.0, 5.0, null, 320.0, "1000", false, 0],
+		["aaaaa2", "BAW22   ", "United Kingdom", 110, 110, -0.4, 51.4, 1000.0, false, 200.0,
+		 90.0, 0.0, null, 1100.0, "2000", false, 0],
+		["aaaaa3", null, "France", 110, 110, 2.3, 48.8, 2000.0, false, 200.0,
+		 90.0, 0.0, null, 2100.0, null, false, 0]]}`
+	watchStates3 = `{"time": 200, "states": [
+		["aaaaa1", "DLH1    ", "Germany", 200, 200, 8.6, 50.1, 300.0, false, 80.0,
+		 90.0, 5.0, null, 320.0, "7700", false, 0],
+		["aaaaa3", null, "France", 200, 200, 2.4, 48.8, null, true, 0.0,
+		 90.0, 0.0, null, null, null, false, 0]]}`
+)
+
+// fakeClock reports the waits of the watcher and releases them when the test fires.
+type fakeClock struct {
+	waits chan time.Duration
+	fire  chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{waits: make(chan time.Duration), fire: make(chan time.Time)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return time.Unix(0, 0)
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits <- d
+
+	return c.fire
+}
+
+var _ = Describe("Watcher", func() {
+	It("emits the traffic changes", func() {
+		conn, err := gopensky.NewConnection(context.Background(), "", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		defer gock.Off()
+
+		for _, body := range []string{watchStates1, watchStates2} {
+			gock.New(gopensky.OpenSkyAPIURL).Get("/states/all").Reply(200).BodyString(body)
+		}
+
+		gock.New(gopensky.OpenSkyAPIURL).Get("/states/all").Reply(500)
+		gock.New(gopensky.OpenSkyAPIURL).Get("/states/all").Reply(200).BodyString(watchStates3)
+
+		gclient, err := gopensky.GetClient(conn)
+		Expect(err).NotTo(HaveOccurred())
+		gock.InterceptClient(gclient)
+
+		clock := newFakeClock()
+
+		var (
+			mu       sync.Mutex
+			events   []string
+			previous []*gopensky.StateVector
+			errs     []error
+		)
+
+		watcher, err := gopensky.NewWatcher(conn,
+			gopensky.WithWatchInterval(10*time.Second),
+			gopensky.WithWatchLostAfter(60*time.Second),
+			gopensky.WithWatchClock(clock),
+			gopensky.WithWatchErrorHandler(func(err error) {
+				errs = append(errs, err)
+			}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(conn)
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			watcher.Run(ctx, func(event gopensky.WatcherEvent) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				events = append(events, event.State.Icao24+" "+event.Type.String())
+
+				if event.Type == gopensky.EventLostContact {
+					previous = append(previous, event.Previous)
+				}
+			})
+		}()
+
+		flush := func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+
+			flushed := events
+			events = nil
+
+			return flushed
+		}
+
+		Expect(<-clock.waits).To(Equal(10 * time.Second))
+		Expect(flush()).To(Equal([]string{"aaaaa1 appeared", "aaaaa2 appeared"}))
+
+		clock.fire <- time.Unix(0, 0)
+		Expect(<-clock.waits).To(Equal(10 * time.Second))
+		Expect(flush()).To(Equal([]string{
+			"aaaaa1 took-off", "aaaaa1 position-updated",
+			"aaaaa2 callsign-changed",
+			"aaaaa3 appeared",
+		}))
+
+		clock.fire <- time.Unix(0, 0)
+		Expect(<-clock.waits).To(Equal(20 * time.Second))
+		Expect(flush()).To(BeEmpty())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).To(MatchError(gopensky.ErrServerUnavailable))
+
+		clock.fire <- time.Unix(0, 0)
+		Expect(<-clock.waits).To(Equal(10 * time.Second))
+		Expect(flush()).To(Equal([]string{
+			"aaaaa1 squawk-changed",
+			"aaaaa3 landed", "aaaaa3 position-updated",
+			"aaaaa2 lost-contact",
+		}))
+		Expect(previous).To(Equal([]*gopensky.StateVector{nil}))
+
+		Expect(gock.IsDone()).To(BeTrue())
+
+		aircraft := watcher.Aircraft()
+		Expect(aircraft).To(HaveLen(2))
+		Expect(aircraft[0].Icao24).To(Equal("aaaaa1"))
+		Expect(aircraft[1].Icao24).To(Equal("aaaaa3"))
+
+		stvec, found := watcher.Lookup("AAAAA1")
+		Expect(found).To(BeTrue())
+		Expect(*stvec.Squawk).To(Equal("7700"))
+
+		_, found = watcher.Lookup("aaaaa2")
+		Expect(found).To(BeFalse())
+
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("sends the events over a channel", func() {
+		conn, err := gopensky.NewConnection(context.Background(), "", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		defer gock.Off()
+		gock.New(gopensky.OpenSkyAPIURL).Get("/states/all").Reply(200).BodyString(watchStates1)
+
+		gclient, err := gopensky.GetClient(conn)
+		Expect(err).NotTo(HaveOccurred())
+		gock.InterceptClient(gclient)
+
+		clock := newFakeClock()
+
+		watcher, err := gopensky.NewWatcher(conn, gopensky.WithWatchClock(clock))
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(conn)
+		events := watcher.Events(ctx)
+
+		event := <-events
+		Expect(event.Type).To(Equal(gopensky.EventAppeared))
+		Expect(event.Time).To(Equal(int64(100)))
+		Expect(event.State.Icao24).To(Equal("aaaaa1"))
+		Expect(event.Previous).To(BeNil())
+
+		event = <-events
+		Expect(event.State.Icao24).To(Equal("aaaaa2"))
+
+		Expect(<-clock.waits).To(Equal(10 * time.Second))
+
+		cancel()
+		Eventually(events).Should(BeClosed())
+	})
+
+	It("tests NewWatcher errors", func() {
+		_, err := gopensky.NewWatcher(context.Background())
+		Expect(err.Error()).To(ContainSubstring("invalid context key"))
+
+		conn, err := gopensky.NewConnection(context.Background(), "", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = gopensky.NewWatcher(conn, gopensky.WithWatchInterval(0))
+		Expect(err.Error()).To(Equal("watcher: invalid watcher duration: 0s"))
+
+		_, err = gopensky.NewWatcher(conn, gopensky.WithWatchICAO24("a835a"))
+		Expect(err).To(MatchError(gopensky.ErrInvalidAircraftName))
+
+		_, err = gopensky.NewWatcher(conn, gopensky.WithWatchBoundingBox(gopensky.NewBoundingBox(10, 0, 0, 10)))
+		Expect(err).To(MatchError(gopensky.ErrInvalidBoundingBox))
+
+		Expect(gopensky.EventLostContact.String()).To(Equal("lost-contact"))
+		Expect(gopensky.WatcherEventType(42).String()).To(Equal("WatcherEventType(42)"))
+	})
+})